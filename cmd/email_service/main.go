@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -9,8 +10,10 @@ import (
 	"rmq_service/pkg/jaeger"
 	"rmq_service/pkg/logger"
 	"rmq_service/pkg/mailer"
+	"rmq_service/pkg/minio"
 	"rmq_service/pkg/postgres"
 	"rmq_service/pkg/rabbitmq"
+	"rmq_service/pkg/redis"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -58,10 +61,25 @@ func main() {
 	defer closer.Close()
 	log.Println("Opentracing connected")
 
-	mailDialer := mailer.NewMailDialer(cfg)
-	log.Println("Mail dialer connected")
+	mailDialers := mailer.NewMailDialers(cfg)
+	log.Printf("Mail dialers connected: %d", len(mailDialers))
 
-	s := server.NewEmailServer(amqpConn, logger.NewApiLogger(cfg), cfg, mailDialer, psqlDB)
+	minioClient, err := minio.NewMinioClient(cfg)
+	if err != nil {
+		log.Fatalf("Minio init: %s", err)
+	}
+	log.Println("Minio connected")
+
+	if err := minio.CreateBucket(context.Background(), minioClient, cfg.AWS.AttachmentsBucket); err != nil {
+		log.Fatalf("Minio create bucket: %s", err)
+	}
+	log.Printf("Minio bucket ready: %s", cfg.AWS.AttachmentsBucket)
+
+	redisClient := redis.NewRedisClient(cfg)
+	defer redisClient.Close()
+	log.Println("Redis connected")
+
+	s := server.NewEmailServer(amqpConn, logger.NewApiLogger(cfg), cfg, mailDialers, psqlDB, minioClient, redisClient)
 
 	log.Fatal(s.Run())
 }
@@ -2,7 +2,9 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -21,6 +23,18 @@ type Config struct {
 	AWS				AWS
 	Jaeger 		Jaeger
 	Smtp 			Smtp
+	SmtpProviders []SmtpProvider
+	SmtpPool 	SmtpPool
+	DKIM 			DKIM
+	Emails 		Emails
+	Unsubscribe Unsubscribe
+	RateLimits 	RateLimits
+	Tenants 		Tenants
+	Quotas 			Quotas
+	Tracking 		Tracking
+	Bounces 		Bounces
+	Webhooks 		Webhooks
+	StatusWebhooks StatusWebhooks
 }
 
 // Server config struct
@@ -28,6 +42,8 @@ type ServerConfig struct {
 	AppVersion				string
 	Port 							string
 	PprofPrort 				string
+	// unsubscribe, tracking and provider webhooks are served here, apart from the metrics
+	PublicPort 				string
 	Mode 							string
 	JwtSecretKey 			string
 	CookieName 				string
@@ -51,6 +67,163 @@ type Smtp struct {
 	Password 			string
 }
 
+// Mail provider, providers with lower priority value are tried first,
+// providers of the same priority share the load by weight.
+// Backend is smtp by default, sendgrid and mailgun send through the provider HTTP API.
+// From is the sender of the provider account, emails keep their sender when it is empty.
+type SmtpProvider struct {
+	Name 					string
+	Backend 			string
+	Host 					string
+	Port 					int
+	User 					string
+	Password 			string
+	APIKey 				string
+	BaseURL 			string
+	Domain 				string
+	From 					string
+	Weight 				int
+	Priority 			int
+}
+
+// Check if provider is sent to over SMTP
+func (p SmtpProvider) IsSMTP() bool {
+	return p.Backend == "" || p.Backend == "smtp"
+}
+
+// Persistent SMTP connections pool of every provider,
+// connection idle longer than HealthCheckAfter is checked before reuse.
+// Every SMTP command has to be answered within CommandTimeout.
+type SmtpPool struct {
+	Size 							int
+	MaxMessages 			int
+	IdleTimeout 			time.Duration
+	HealthCheckAfter 	time.Duration
+	WaitTimeout 			time.Duration
+	DialTimeout 			time.Duration
+	CommandTimeout 		time.Duration
+}
+
+// DKIM signing of outgoing mail, domain keys are reloaded from the config file on SIGHUP
+type DKIM struct {
+	Enabled bool
+	Headers []string
+	Keys 		[]DKIMKey
+}
+
+// DKIM selector and PEM private key file of the sending domain, RSA and Ed25519 keys are supported
+type DKIMKey struct {
+	Domain 		string
+	Selector 	string
+	KeyFile 	string
+}
+
+// Emails processing config
+type Emails struct {
+	IdempotencyWindow 	time.Duration
+	SchedulerInterval 	time.Duration
+	SchedulerBatchSize 	int
+	SchedulerLease 			time.Duration
+	MaxAttachmentSize 	int64
+	MaxAttachmentsSize 	int64
+	AttachmentBlobThreshold int64
+	MaxBulkRecipients 	int
+}
+
+// One-click unsubscribe config
+type Unsubscribe struct {
+	Secret 		string
+	BaseURL 	string
+	TokenTTL 	time.Duration
+}
+
+// Open and click tracking, tracking is enabled when secret and base URL are set
+type Tracking struct {
+	Secret 	string
+	BaseURL string
+}
+
+// Bounce processing, raw MIME bounce reports are consumed from the bounces queue.
+// SMTP emails are sent with VERP envelope sender of the return path, e.g. bounces+<email id>@example.com,
+// sender address is the envelope sender when return path is not set.
+type Bounces struct {
+	ReturnPath 			string
+	Exchange 				string
+	Queue 					string
+	RoutingKey 			string
+	ConsumerTag 		string
+	WorkerPoolSize 	int
+}
+
+// Provider delivery event webhooks are posted to /webhooks/<provider>,
+// webhook of the provider is enabled when its verification key is set
+type Webhooks struct {
+	// base64 public key of the SendGrid signed event webhook
+	SendGridPublicKey string
+	MailgunSigningKey string
+	// max size of the webhook request body in bytes
+	MaxBodySize 			int64
+	// signed requests older than the max age are rejected as replayed
+	MaxEventAge 			time.Duration
+}
+
+// Status webhooks of the callers, events are posted to the webhook URL of the email or its tenant,
+// signed with the tenant webhook secret or the configured secret for emails of the default tenant.
+// Failed deliveries are retried from the outbox with doubling delay until MaxAttempts.
+// Webhooks are posted to public addresses only, https is required outside Development mode.
+type StatusWebhooks struct {
+	Secret 					string
+	Interval 				time.Duration
+	BatchSize 			int
+	Lease 					time.Duration
+	Timeout 				time.Duration
+	MaxAttempts 		int
+	RetryBaseDelay 	time.Duration
+	MaxRetryDelay 	time.Duration
+}
+
+// Tenants authentication, tenant API key is sent in x-api-key metadata and
+// admin key in x-admin-key metadata. Tenant admin RPCs are disabled without admin key.
+type Tenants struct {
+	AdminKey 	string
+	// reject requests without tenant API key
+	Required 	bool
+	// base64 encoded 32 bytes key encrypting provider passwords and API keys of the tenants
+	SecretsKey string
+}
+
+// Recipients accepted per UTC day and month, zero quota is unlimited.
+// Tenant quotas apply to tenants which have no quotas of their own.
+type Quotas struct {
+	TenantDaily 	int64
+	TenantMonthly int64
+	SenderDaily 	int64
+	SenderMonthly int64
+}
+
+// Send rate limits, limit with zero rate is disabled
+type RateLimits struct {
+	KeyPrefix string
+	MaxDelay 	time.Duration
+	Global 		RateLimit
+	// every provider account chosen by the router has its own bucket
+	Account 	RateLimit
+	Domain 		RateLimit
+	Domains 	[]DomainRateLimit
+}
+
+// Token bucket rate limit, rate is tokens per second
+type RateLimit struct {
+	Rate 	float64
+	Burst int
+}
+
+// Rate limit of the recipient domain, overrides the default domain limit
+type DomainRateLimit struct {
+	Domain string
+	RateLimit `mapstructure:",squash"`
+}
+
 // RabbitMQ
 type RabbitMQ struct {
 	Host 						string
@@ -59,9 +232,21 @@ type RabbitMQ struct {
 	Password 				string
 	Exchange 				string
 	Queue 					string
+	// queue declared before priorities, it is unbound and its left messages are consumed
+	LegacyQueue 		string
 	RoutingKey 			string
 	ConsumerTag 		string
 	WorkerPoolSize	int
+	RetryExchange 	string
+	RetryBaseDelay 	time.Duration
+	RetryTiers 			int
+	// rate limited messages wait in the throttle tier of the nearest longer delay
+	ThrottleBaseDelay time.Duration
+	ThrottleTiers 	int
+	MaxAttempts 		int
+	ParkingQueue 		string
+	// topic exchange of the email lifecycle events, events are not published when empty
+	EventsExchange 	string
 }
 
 // Logger config
@@ -125,6 +310,7 @@ type AWS struct {
 	MinioSecretKey 	string
 	UseSSL 					bool
 	MinioEndpoint 	string
+	AttachmentsBucket string
 }
 
 // Jaeger
@@ -140,6 +326,8 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 
 	v.SetConfigName(filename)
 	v.AddConfigPath(".")
+	// nested keys are read from env as well, e.g. UNSUBSCRIBE_SECRET
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
 	if err := v.ReadInConfig(); err != nil {
@@ -177,9 +365,44 @@ func GetConfig(confPath string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.validateSmtpProviders(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// Check SMTP providers have unique names, dialers and pools are keyed by provider name
+func (c *Config) validateSmtpProviders() error {
+	names := make(map[string]struct{}, len(c.SmtpProviders))
+	for _, p := range c.SmtpProviders {
+		if p.Name == "" {
+			return errors.New("SMTP provider name is required")
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicate SMTP provider name: %s", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Get SMTP providers, the single Smtp config is used when no providers are configured
+func (c *Config) GetSmtpProviders() []SmtpProvider {
+	if len(c.SmtpProviders) > 0 {
+		return c.SmtpProviders
+	}
+
+	return []SmtpProvider{{
+		Name: 		"default",
+		Host: 		c.Smtp.Host,
+		Port: 		c.Smtp.Port,
+		User: 		c.Smtp.User,
+		Password: c.Smtp.Password,
+		Weight: 	1,
+	}}
+}
+
 func GetConfigPath(confPath string) string {
 	if confPath == "docker" {
 		return "./config/config-docker"
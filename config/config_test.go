@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestValidateSmtpProviders(t *testing.T) {
+	tests := []struct {
+		name 			string
+		providers []SmtpProvider
+		wantErr 	bool
+	}{
+		{"no providers", nil, false},
+		{"unique names", []SmtpProvider{{Name: "gmail"}, {Name: "mailgun"}}, false},
+		{"duplicate names", []SmtpProvider{{Name: "gmail"}, {Name: "mailgun"}, {Name: "gmail"}}, true},
+		{"empty name", []SmtpProvider{{Name: ""}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{SmtpProviders: tt.providers}
+			if err := cfg.validateSmtpProviders(); (err != nil) != tt.wantErr {
+				t.Fatalf("validateSmtpProviders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
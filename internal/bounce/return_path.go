@@ -0,0 +1,55 @@
+package bounce
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const verpSeparator = "+"
+
+// VERP return path, bounces of the email are sent to local+<email id>@domain of the configured address
+type ReturnPath struct {
+	local 	string
+	domain 	string
+}
+
+// Return path constructor, return path without address or domain is disabled
+func NewReturnPath(address string) *ReturnPath {
+	address = strings.TrimSpace(strings.ToLower(address))
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return &ReturnPath{}
+	}
+	return &ReturnPath{local: address[:at], domain: address[at+1:]}
+}
+
+// Check if emails are sent with VERP envelope sender
+func (r *ReturnPath) Enabled() bool {
+	return r != nil && r.domain != ""
+}
+
+// VERP envelope sender of the email
+func (r *ReturnPath) Address(emailID uuid.UUID) string {
+	return r.local + verpSeparator + emailID.String() + "@" + r.domain
+}
+
+// Email id of the VERP address, false is returned for any other address
+func (r *ReturnPath) EmailID(address string) (uuid.UUID, bool) {
+	if !r.Enabled() {
+		return uuid.Nil, false
+	}
+
+	address = strings.ToLower(address)
+	prefix := r.local + verpSeparator
+	suffix := "@" + r.domain
+	if !strings.HasPrefix(address, prefix) || !strings.HasSuffix(address, suffix) {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(address[len(prefix) : len(address)-len(suffix)])
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,13 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package bounce
+
+import (
+	"context"
+	"rmq_service/internal/models"
+)
+
+// Bounces useCase interface
+type BouncesUseCase interface {
+	ProcessReport(ctx context.Context, message []byte) ([]*models.DeliveryEvent, error)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"rmq_service/internal/bounce"
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/dsn"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+)
+
+// Bounces usecase struct
+type BouncesUseCase struct {
+	emailsRepo 	email.EmailsRepository
+	emailUC 		email.EmailsUseCase
+	returnPath 	*bounce.ReturnPath
+	logger 			logger.Logger
+}
+
+// BouncesUseCase constructor
+func NewBouncesUseCase(
+	emailsRepo email.EmailsRepository,
+	emailUC email.EmailsUseCase,
+	returnPath *bounce.ReturnPath,
+	logger logger.Logger,
+) *BouncesUseCase {
+	return &BouncesUseCase{
+		emailsRepo: emailsRepo,
+		emailUC: 		emailUC,
+		returnPath: returnPath,
+		logger: 		logger,
+	}
+}
+
+// Process raw delivery status notification or feedback report,
+// bounces and complaints are applied to the email as its delivery events
+func (u *BouncesUseCase) ProcessReport(ctx context.Context, message []byte) ([]*models.DeliveryEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BouncesUseCase.ProcessReport")
+	defer span.Finish()
+
+	report, err := dsn.Parse(bytes.NewReader(message))
+	if err != nil {
+		return nil, errors.Wrapf(grpc_errors.ErrInvalidBounceReport, "dsn.Parse: %v", err)
+	}
+
+	emailID, err := u.matchEmail(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := u.emailsRepo.FindEmailRecipients(ctx, emailID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(grpc_errors.ErrEmailNotMatched, "email %v", emailID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "emailsRepo.FindEmailRecipients")
+	}
+
+	bounces := u.reportBounces(emailID, report, recipients)
+	for _, b := range bounces {
+		if err := u.emailUC.ApplyDeliveryEvent(ctx, b); err != nil {
+			return nil, errors.Wrap(err, "emailUC.ApplyDeliveryEvent")
+		}
+	}
+
+	span.LogFields(log.String("emailID", emailID.String()), log.Int("bounces", len(bounces)))
+	return bounces, nil
+}
+
+// Match report to the email by VERP address, then by Message-ID of the original message
+func (u *BouncesUseCase) matchEmail(ctx context.Context, report *dsn.Report) (uuid.UUID, error) {
+	for _, address := range report.Addressees {
+		if id, ok := u.returnPath.EmailID(address); ok {
+			return id, nil
+		}
+	}
+
+	if report.MessageID != "" {
+		id, err := u.emailsRepo.FindEmailIdByMessageID(ctx, report.MessageID)
+		if err == nil {
+			return id, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, errors.Wrap(err, "emailsRepo.FindEmailIdByMessageID")
+		}
+	}
+
+	return uuid.Nil, errors.Wrapf(grpc_errors.ErrEmailNotMatched, "message id %q", report.MessageID)
+}
+
+// Bounces of the reported recipients, recipients the email was not sent to are skipped.
+// Complaint about the single recipient email is about its recipient, even when the provider redacted it.
+func (u *BouncesUseCase) reportBounces(emailID uuid.UUID, report *dsn.Report, recipients []string) []*models.DeliveryEvent {
+	sent := make(map[string]struct{}, len(recipients))
+	for _, r := range recipients {
+		sent[strings.ToLower(r)] = struct{}{}
+	}
+
+	reported := report.Recipients
+	if report.Type == dsn.ReportTypeFeedback && len(recipients) == 1 {
+		reported = []*dsn.Recipient{{Address: strings.ToLower(recipients[0])}}
+	}
+
+	bounces := make([]*models.DeliveryEvent, 0, len(reported))
+	for _, r := range reported {
+		if _, ok := sent[r.Address]; !ok {
+			u.logger.Warnf("Skip bounce of %s, email %v was not sent to it", r.Address, emailID)
+			continue
+		}
+
+		b := &models.DeliveryEvent{EmailID: emailID, Recipient: r.Address}
+		switch {
+		case report.Type == dsn.ReportTypeFeedback:
+			b.Type = models.EmailEventComplaint
+			b.Reason = report.FeedbackType
+		case r.IsHardBounce():
+			b.Type = models.EmailEventBounce
+			b.Reason = strings.TrimSpace(r.Status + " " + r.DiagnosticCode)
+		case r.IsSoftBounce():
+			b.Type = models.EmailEventSoftBounce
+			b.Reason = strings.TrimSpace(r.Status + " " + r.DiagnosticCode)
+		default:
+			// successful delivery notifications
+			continue
+		}
+		bounces = append(bounces, b)
+	}
+	return bounces
+}
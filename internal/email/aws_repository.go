@@ -0,0 +1,15 @@
+//go:generate mockgen -source aws_repository.go -destination mock/aws_repository.go -package mock
+
+package email
+
+import (
+	"context"
+)
+
+// Attachments AWS S3 repository interface
+type AttachmentsAWSRepository interface {
+	PutAttachment(ctx context.Context, key, contentType string, content []byte) error
+	GetAttachment(ctx context.Context, key string) ([]byte, error)
+	GetAttachmentSize(ctx context.Context, key string) (int64, error)
+	DeleteAttachments(ctx context.Context, prefix string) error
+}
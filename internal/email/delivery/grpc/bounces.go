@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/status"
+)
+
+// Process uploaded bounce report
+func (e *EmailMicroservice) ProcessBounceReport(
+	ctx context.Context,
+	r *emailService.ProcessBounceReportRequest) (*emailService.ProcessBounceReportResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ProcessBounceReport")
+	defer span.Finish()
+
+	bounces, err := e.bounceUC.ProcessReport(ctx, r.GetMessage())
+	if err != nil {
+		e.logger.Errorf("bounceUC.ProcessReport: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "bounceUC.ProcessReport: %v", err)
+	}
+
+	protoBounces := make([]*emailService.Bounce, 0, len(bounces))
+	for _, b := range bounces {
+		protoBounces = append(protoBounces, e.convertDeliveryEventToBounceProto(b))
+	}
+
+	return &emailService.ProcessBounceReportResponse{Bounces: protoBounces}, nil
+}
+
+func (e *EmailMicroservice) convertDeliveryEventToBounceProto(b *models.DeliveryEvent) *emailService.Bounce {
+	return &emailService.Bounce{
+		EmailId: 		b.EmailID.String(),
+		Type: 			b.Type,
+		Recipient: 	b.Recipient,
+		Reason: 		b.Reason,
+	}
+}
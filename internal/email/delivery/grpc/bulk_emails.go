@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Send bulk emails, one email per recipient
+func (e *EmailMicroservice) SendBulkEmails(
+	ctx context.Context,
+	r *emailService.SendBulkEmailsRequest) (*emailService.SendBulkEmailsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.SendBulkEmails")
+	defer span.Finish()
+
+	bulk := &models.BulkEmail{
+		Subject: 			r.GetSubject(),
+		Body: 				r.GetBody(),
+		TextBody: 		r.GetTextBody(),
+		ContentType: 	r.GetContentType(),
+		TemplateVersion: int(r.GetTemplateVersion()),
+		ReplyTo: 			r.GetReplyTo(),
+		Headers: 			r.GetHeaders(),
+		Category: 		r.GetCategory(),
+		Priority: 		r.GetPriority(),
+		Track: 				r.GetTrack(),
+		WebhookURL: 	r.GetWebhookUrl(),
+		Recipients: 	make([]*models.BulkRecipient, 0, len(r.GetRecipients())),
+	}
+
+	for _, recipient := range r.GetRecipients() {
+		bulk.Recipients = append(bulk.Recipients, &models.BulkRecipient{
+			To: 						recipient.GetTo(),
+			Variables: 			recipient.GetVariables(),
+			IdempotencyKey: recipient.GetIdempotencyKey(),
+		})
+	}
+
+	if r.GetTemplateId() != "" {
+		templateID, err := uuid.Parse(r.GetTemplateId())
+		if err != nil {
+			e.logger.Errorf("uuid.Parse: %v", err)
+			return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+		}
+		bulk.TemplateID = &templateID
+	}
+
+	if r.GetSendAt() != nil {
+		sendAt := r.GetSendAt().AsTime()
+		bulk.SendAt = &sendAt
+	}
+
+	batch, err := e.emailUC.SendBulkEmails(ctx, bulk)
+	if err != nil {
+		e.logger.Errorf("emailUC.SendBulkEmails: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.SendBulkEmails: %v", err)
+	}
+
+	failed := make([]*emailService.BulkFailedRecipient, 0, len(batch.Failed))
+	for _, f := range batch.Failed {
+		failed = append(failed, &emailService.BulkFailedRecipient{To: f.To, Error: f.Error})
+	}
+
+	return &emailService.SendBulkEmailsResponse{
+		Status: 	"Ok",
+		BatchId: 	batch.BatchID.String(),
+		Total: 		uint64(batch.Total),
+		Failed: 	failed,
+	}, nil
+}
+
+// Get bulk emails progress
+func (e *EmailMicroservice) GetBulkEmailsProgress(
+	ctx context.Context,
+	r *emailService.GetBulkEmailsProgressRequest) (*emailService.GetBulkEmailsProgressResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetBulkEmailsProgress")
+	defer span.Finish()
+
+	batchID, err := uuid.Parse(r.GetBatchId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	progress, err := e.emailUC.GetEmailBatchProgress(ctx, batchID)
+	if err != nil {
+		e.logger.Errorf("emailUC.GetEmailBatchProgress: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.GetEmailBatchProgress: %v", err)
+	}
+
+	return &emailService.GetBulkEmailsProgressResponse{
+		BatchId: 		progress.BatchID.String(),
+		Total: 			uint64(progress.Total),
+		Statuses: 	progress.Statuses,
+		Pending: 		progress.Pending(),
+		CreatedAt: 	timestamppb.New(progress.CreatedAt),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Get email events
+func (e *EmailMicroservice) GetEmailEvents(
+	ctx context.Context,
+	r *emailService.GetEmailEventsRequest) (*emailService.GetEmailEventsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetEmailEvents")
+	defer span.Finish()
+
+	emailID, err := uuid.Parse(r.GetEmailId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	events, err := e.emailUC.GetEmailEvents(ctx, emailID)
+	if err != nil {
+		e.logger.Errorf("emailUC.GetEmailEvents: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.GetEmailEvents: %v", err)
+	}
+
+	protoEvents := make([]*emailService.EmailEvent, 0, len(events))
+	for _, event := range events {
+		protoEvents = append(protoEvents, e.convertEmailEventToProto(event))
+	}
+
+	return &emailService.GetEmailEventsResponse{Events: protoEvents}, nil
+}
+
+func (e *EmailMicroservice) convertEmailEventToProto(event *models.EmailEvent) *emailService.EmailEvent {
+	return &emailService.EmailEvent{
+		EventId: 		event.EventID,
+		EmailId: 		event.EmailID.String(),
+		Type: 			event.Type,
+		Url: 				event.URL,
+		UserAgent: 	event.UserAgent,
+		Ip: 				event.IP,
+		CreatedAt: 	timestamppb.New(event.CreatedAt),
+		Recipient: 	event.Recipient,
+		Reason: 		event.Reason,
+	}
+}
@@ -3,15 +3,22 @@ package grpc
 import (
 	"context"
 	"rmq_service/config"
+	"rmq_service/internal/bounce"
 	"rmq_service/internal/email"
 	emailService "rmq_service/internal/email/proto"
 	"rmq_service/internal/models"
+	"rmq_service/internal/quota"
+	"rmq_service/internal/suppression"
+	"rmq_service/internal/template"
+	"rmq_service/internal/tenant"
+	"rmq_service/internal/webhook"
 	"rmq_service/pkg/grpc_errors"
 	"rmq_service/pkg/logger"
 	"rmq_service/pkg/utils"
 
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -22,11 +29,36 @@ type EmailMicroservice struct {
 	cfg 			*config.Config
 	logger 		logger.Logger
 	emailUC 	email.EmailsUseCase
+	templateUC template.TemplatesUseCase
+	suppressionUC suppression.SuppressionsUseCase
+	tenantUC 	tenant.TenantsUseCase
+	quotaUC 	quota.QuotasUseCase
+	bounceUC 	bounce.BouncesUseCase
+	webhookUC webhook.WebhooksUseCase
 }
 
 // Email gRPC microservice constructor
-func NewEmailMicroservice(cfg *config.Config, logger logger.Logger, emailUC email.EmailsUseCase) *EmailMicroservice	{
-	return &EmailMicroservice{ cfg: cfg, logger: logger, emailUC: emailUC }
+func NewEmailMicroservice(
+	cfg *config.Config,
+	logger logger.Logger,
+	emailUC email.EmailsUseCase,
+	templateUC template.TemplatesUseCase,
+	suppressionUC suppression.SuppressionsUseCase,
+	tenantUC tenant.TenantsUseCase,
+	quotaUC quota.QuotasUseCase,
+	bounceUC bounce.BouncesUseCase,
+	webhookUC webhook.WebhooksUseCase) *EmailMicroservice	{
+	return &EmailMicroservice{
+		cfg: cfg,
+		logger: logger,
+		emailUC: emailUC,
+		templateUC: templateUC,
+		suppressionUC: suppressionUC,
+		tenantUC: tenantUC,
+		quotaUC: quotaUC,
+		bounceUC: bounceUC,
+		webhookUC: webhookUC,
+	}
 }
 
 // Send Emails
@@ -35,10 +67,44 @@ func (e *EmailMicroservice) SendEmails(ctx context.Context, r *emailService.Send
 	defer span.Finish()
 
 	mail := &models.Email{
-		From: 		e.cfg.Smtp.User,
+		From: 		tenant.SenderFromContext(ctx, e.cfg.Smtp.User),
 		To: 			r.GetTo(),
 		Body: 		r.GetBody(),
+		TextBody: r.GetTextBody(),
+		ContentType: r.GetContentType(),
+		Category: r.GetCategory(),
+		Priority: r.GetPriority(),
+		Track: 		r.GetTrack(),
+		WebhookURL: r.GetWebhookUrl(),
 		Subject: 	r.GetSubject(),
+		IdempotencyKey: r.GetIdempotencyKey(),
+		Attachments: e.convertAttachmentsFromProto(r.GetAttachments()),
+		Cc: 			r.GetCc(),
+		Bcc: 			r.GetBcc(),
+		ReplyTo: 	r.GetReplyTo(),
+		Headers: 	r.GetHeaders(),
+	}
+
+	if r.GetSendAt() != nil {
+		sendAt := r.GetSendAt().AsTime()
+		mail.SendAt = &sendAt
+	}
+
+	if r.GetTemplateId() != "" {
+		templateID, err := uuid.Parse(r.GetTemplateId())
+		if err != nil {
+			e.logger.Errorf("uuid.Parse: %v", err)
+			return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+		}
+
+		mail.TemplateID = &templateID
+		mail.TemplateVersion = int(r.GetTemplateVersion())
+		mail.Variables = r.GetVariables()
+
+		if err := e.emailUC.RenderEmailTemplate(ctx, mail); err != nil {
+			e.logger.Errorf("emailUC.RenderEmailTemplate: %v", err)
+			return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.RenderEmailTemplate: %v", err)
+		}
 	}
 
 	if err := mail.PrepareAndValidate(ctx); err != nil {
@@ -46,7 +112,12 @@ func (e *EmailMicroservice) SendEmails(ctx context.Context, r *emailService.Send
 		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.publishEmailToQueue: %v", err)
 	}
 
-	return &emailService.SendEmailsResponse{Status: "Ok"}, nil
+	if err := e.emailUC.PublishEmailToQueue(ctx, mail); err != nil {
+		e.logger.Errorf("emailUC.PublishEmailToQueue: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.PublishEmailToQueue: %v", err)
+	}
+
+	return &emailService.SendEmailsResponse{Status: "Ok", EmailId: mail.EmailID.String()}, nil
 }
 
 // Find email by id
@@ -100,7 +171,7 @@ func (e *EmailMicroservice) FindEmailsByReceiver(
 }
 
 func (e *EmailMicroservice) convertEmailToProto(email *models.Email) *emailService.Email {
-	return &emailService.Email{
+	protoEmail := &emailService.Email{
 		EmailId: 			email.EmailID.String(),
 		To: 					email.To,
 		From: 				email.From,
@@ -108,7 +179,43 @@ func (e *EmailMicroservice) convertEmailToProto(email *models.Email) *emailServi
 		Subject:  		email.Subject,
 		ContentType: 	email.ContentType,
 		CreatedAt: 		timestamppb.New(email.CreatedAt),
+		Status: 			email.Status,
+		StatusReason: email.StatusReason,
+		UpdatedAt: 		timestamppb.New(email.UpdatedAt),
+		Cc: 					email.Cc,
+		ReplyTo: 			email.ReplyTo,
+		Headers: 			email.Headers,
+		TextBody: 		email.TextBody,
+		Category: 		email.Category,
+		Provider: 		email.Provider,
+		ProviderMessageId: email.ProviderMessageID,
+		Priority: 		email.Priority,
+		WebhookUrl: 	email.WebhookURL,
+	}
+
+	if email.SendAt != nil {
+		protoEmail.SendAt = timestamppb.New(*email.SendAt)
+	}
+
+	if email.TenantID != nil {
+		protoEmail.TenantId = email.TenantID.String()
+	}
+
+	return protoEmail
+}
+
+func (e *EmailMicroservice) convertAttachmentsFromProto(attachments []*emailService.Attachment) []*models.Attachment {
+	modelAttachments := make([]*models.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		modelAttachments = append(modelAttachments, &models.Attachment{
+			Filename: 		a.GetFilename(),
+			ContentType: 	a.GetContentType(),
+			Content: 			a.GetContent(),
+			BlobKey: 			a.GetBlobKey(),
+			Inline: 			a.GetInline(),
+		})
 	}
+	return modelAttachments
 }
 
 func (e *EmailMicroservice) convertEmailsListToProto(emails []*models.Email) []*emailService.Email {
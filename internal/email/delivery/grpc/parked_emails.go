@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// List parked emails
+func (e *EmailMicroservice) ListParkedEmails(
+	ctx context.Context,
+	r *emailService.ListParkedEmailsRequest) (*emailService.ListParkedEmailsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ListParkedEmails")
+	defer span.Finish()
+
+	filter := e.convertParkedFilterFromProto(r.GetFilter())
+	filter.Limit = int(r.GetLimit())
+
+	parked, err := e.emailUC.ListParkedEmails(ctx, filter)
+	if err != nil {
+		e.logger.Errorf("emailUC.ListParkedEmails: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.ListParkedEmails: %v", err)
+	}
+
+	protoParked := make([]*emailService.ParkedEmail, 0, len(parked))
+	for _, p := range parked {
+		protoParked = append(protoParked, e.convertParkedEmailToProto(p))
+	}
+
+	return &emailService.ListParkedEmailsResponse{Emails: protoParked}, nil
+}
+
+// Get parked email
+func (e *EmailMicroservice) GetParkedEmail(
+	ctx context.Context,
+	r *emailService.GetParkedEmailRequest) (*emailService.GetParkedEmailResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetParkedEmail")
+	defer span.Finish()
+
+	parked, err := e.emailUC.GetParkedEmail(ctx, r.GetMessageId())
+	if err != nil {
+		e.logger.Errorf("emailUC.GetParkedEmail: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.GetParkedEmail: %v", err)
+	}
+
+	return &emailService.GetParkedEmailResponse{Email: e.convertParkedEmailToProto(parked)}, nil
+}
+
+// Replay parked emails
+func (e *EmailMicroservice) ReplayParkedEmails(
+	ctx context.Context,
+	r *emailService.ReplayParkedEmailsRequest) (*emailService.ReplayParkedEmailsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ReplayParkedEmails")
+	defer span.Finish()
+
+	replayed, err := e.emailUC.ReplayParkedEmails(ctx, e.convertParkedFilterFromProto(r.GetFilter()))
+	if err != nil {
+		e.logger.Errorf("emailUC.ReplayParkedEmails: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.ReplayParkedEmails: %v", err)
+	}
+
+	return &emailService.ReplayParkedEmailsResponse{Replayed: uint64(replayed)}, nil
+}
+
+// Purge parked emails
+func (e *EmailMicroservice) PurgeParkedEmails(
+	ctx context.Context,
+	r *emailService.PurgeParkedEmailsRequest) (*emailService.PurgeParkedEmailsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.PurgeParkedEmails")
+	defer span.Finish()
+
+	purged, err := e.emailUC.PurgeParkedEmails(ctx, e.convertParkedFilterFromProto(r.GetFilter()), r.GetAll())
+	if err != nil {
+		e.logger.Errorf("emailUC.PurgeParkedEmails: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "emailUC.PurgeParkedEmails: %v", err)
+	}
+
+	return &emailService.PurgeParkedEmailsResponse{Purged: uint64(purged)}, nil
+}
+
+func (e *EmailMicroservice) convertParkedFilterFromProto(filter *emailService.ParkedEmailsFilter) *models.ParkedEmailsFilter {
+	return &models.ParkedEmailsFilter{
+		MessageID: 			filter.GetMessageId(),
+		To: 						filter.GetReceiverEmail(),
+		ReasonContains: filter.GetReasonContains(),
+	}
+}
+
+func (e *EmailMicroservice) convertParkedEmailToProto(parked *models.ParkedEmail) *emailService.ParkedEmail {
+	protoParked := &emailService.ParkedEmail{
+		MessageId: 			parked.MessageID,
+		FailureReason: 	parked.FailureReason,
+		Attempts: 			parked.Attempts,
+		FailedAt: 			timestamppb.New(parked.FailedAt),
+	}
+
+	if parked.Email != nil {
+		protoParked.Email = e.convertEmailToProto(parked.Email)
+	}
+
+	return protoParked
+}
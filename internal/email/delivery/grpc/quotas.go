@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Get quota usage of the request tenant and its sender
+func (e *EmailMicroservice) GetUsage(
+	ctx context.Context,
+	r *emailService.GetUsageRequest) (*emailService.GetUsageResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetUsage")
+	defer span.Finish()
+
+	usages, err := e.quotaUC.GetUsage(ctx, tenant.SenderFromContext(ctx, e.cfg.Smtp.User))
+	if err != nil {
+		e.logger.Errorf("quotaUC.GetUsage: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "quotaUC.GetUsage: %v", err)
+	}
+
+	protoUsages := make([]*emailService.QuotaUsage, 0, len(usages))
+	for _, u := range usages {
+		protoUsages = append(protoUsages, e.convertQuotaUsageToProto(u))
+	}
+
+	return &emailService.GetUsageResponse{Usages: protoUsages}, nil
+}
+
+func (e *EmailMicroservice) convertQuotaUsageToProto(u *models.QuotaUsage) *emailService.QuotaUsage {
+	return &emailService.QuotaUsage{
+		Scope: 				u.Scope,
+		Key: 					u.Key,
+		Period: 			u.Period,
+		PeriodStart: 	timestamppb.New(u.PeriodStart),
+		ResetsAt: 		timestamppb.New(u.GetResetsAt()),
+		Used: 				uint64(u.Used),
+		Limit: 				uint64(u.Limit),
+		Remaining: 		uint64(u.GetRemaining()),
+		Unlimited: 		!u.IsLimited(),
+	}
+}
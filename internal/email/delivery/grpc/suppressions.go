@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Add suppression
+func (e *EmailMicroservice) AddSuppression(
+	ctx context.Context,
+	r *emailService.AddSuppressionRequest) (*emailService.AddSuppressionResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.AddSuppression")
+	defer span.Finish()
+
+	added, err := e.suppressionUC.AddSuppression(ctx, e.convertSuppressionFromProto(r.GetSuppression()))
+	if err != nil {
+		e.logger.Errorf("suppressionUC.AddSuppression: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "suppressionUC.AddSuppression: %v", err)
+	}
+
+	return &emailService.AddSuppressionResponse{Suppression: e.convertSuppressionToProto(added)}, nil
+}
+
+// Remove suppression
+func (e *EmailMicroservice) RemoveSuppression(
+	ctx context.Context,
+	r *emailService.RemoveSuppressionRequest) (*emailService.RemoveSuppressionResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.RemoveSuppression")
+	defer span.Finish()
+
+	if err := e.suppressionUC.RemoveSuppression(ctx, r.GetAddress(), r.GetCategory()); err != nil {
+		e.logger.Errorf("suppressionUC.RemoveSuppression: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "suppressionUC.RemoveSuppression: %v", err)
+	}
+
+	return &emailService.RemoveSuppressionResponse{Status: "Ok"}, nil
+}
+
+// List suppressions
+func (e *EmailMicroservice) ListSuppressions(
+	ctx context.Context,
+	r *emailService.ListSuppressionsRequest) (*emailService.ListSuppressionsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ListSuppressions")
+	defer span.Finish()
+
+	suppressions, err := e.suppressionUC.ListSuppressions(ctx, r.GetSearch(), &utils.PaginationQuery{
+		Size: r.GetSize(),
+		Page: r.GetPage(),
+	})
+	if err != nil {
+		e.logger.Errorf("suppressionUC.ListSuppressions: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "suppressionUC.ListSuppressions: %v", err)
+	}
+
+	protoSuppressions := make([]*emailService.Suppression, 0, len(suppressions.Suppressions))
+	for _, s := range suppressions.Suppressions {
+		protoSuppressions = append(protoSuppressions, e.convertSuppressionToProto(s))
+	}
+
+	return &emailService.ListSuppressionsResponse{
+		Suppressions: protoSuppressions,
+		TotalPages: 	suppressions.TotalPages,
+		TotalCount: 	suppressions.TotalCount,
+		HasMore: 			suppressions.HasMore,
+		Page: 				suppressions.Page,
+		Size: 				suppressions.Size,
+	}, nil
+}
+
+// Import suppressions
+func (e *EmailMicroservice) ImportSuppressions(
+	ctx context.Context,
+	r *emailService.ImportSuppressionsRequest) (*emailService.ImportSuppressionsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ImportSuppressions")
+	defer span.Finish()
+
+	suppressions := make([]*models.Suppression, 0, len(r.GetSuppressions()))
+	for _, s := range r.GetSuppressions() {
+		suppressions = append(suppressions, e.convertSuppressionFromProto(s))
+	}
+
+	imported, err := e.suppressionUC.ImportSuppressions(ctx, suppressions)
+	if err != nil {
+		e.logger.Errorf("suppressionUC.ImportSuppressions: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "suppressionUC.ImportSuppressions: %v", err)
+	}
+
+	return &emailService.ImportSuppressionsResponse{Imported: uint64(imported)}, nil
+}
+
+func (e *EmailMicroservice) convertSuppressionFromProto(s *emailService.Suppression) *models.Suppression {
+	suppression := &models.Suppression{
+		Address: 	s.GetAddress(),
+		Category: s.GetCategory(),
+		Reason: 	s.GetReason(),
+	}
+
+	if s.GetExpiresAt() != nil {
+		expiresAt := s.GetExpiresAt().AsTime()
+		suppression.ExpiresAt = &expiresAt
+	}
+
+	return suppression
+}
+
+func (e *EmailMicroservice) convertSuppressionToProto(s *models.Suppression) *emailService.Suppression {
+	protoSuppression := &emailService.Suppression{
+		Address: 		s.Address,
+		Category: 	s.Category,
+		Reason: 		s.Reason,
+		CreatedAt: 	timestamppb.New(s.CreatedAt),
+	}
+
+	if s.ExpiresAt != nil {
+		protoSuppression.ExpiresAt = timestamppb.New(*s.ExpiresAt)
+	}
+
+	return protoSuppression
+}
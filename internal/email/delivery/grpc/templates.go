@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Create template
+func (e *EmailMicroservice) CreateTemplate(
+	ctx context.Context,
+	r *emailService.CreateTemplateRequest) (*emailService.CreateTemplateResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.CreateTemplate")
+	defer span.Finish()
+
+	created, err := e.templateUC.CreateTemplate(ctx, &models.Template{
+		Name: 		r.GetName(),
+		Subject: 	r.GetSubject(),
+		HTMLBody: r.GetHtmlBody(),
+		TextBody: r.GetTextBody(),
+	})
+	if err != nil {
+		e.logger.Errorf("templateUC.CreateTemplate: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "templateUC.CreateTemplate: %v", err)
+	}
+
+	return &emailService.CreateTemplateResponse{Template: e.convertTemplateToProto(created)}, nil
+}
+
+// Get template
+func (e *EmailMicroservice) GetTemplate(
+	ctx context.Context,
+	r *emailService.GetTemplateRequest) (*emailService.GetTemplateResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetTemplate")
+	defer span.Finish()
+
+	templateID, err := uuid.Parse(r.GetTemplateId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	t, err := e.templateUC.GetTemplate(ctx, templateID, int(r.GetVersion()))
+	if err != nil {
+		e.logger.Errorf("templateUC.GetTemplate: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "templateUC.GetTemplate: %v", err)
+	}
+
+	return &emailService.GetTemplateResponse{Template: e.convertTemplateToProto(t)}, nil
+}
+
+// Update template
+func (e *EmailMicroservice) UpdateTemplate(
+	ctx context.Context,
+	r *emailService.UpdateTemplateRequest) (*emailService.UpdateTemplateResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.UpdateTemplate")
+	defer span.Finish()
+
+	templateID, err := uuid.Parse(r.GetTemplateId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	updated, err := e.templateUC.UpdateTemplate(ctx, &models.Template{
+		TemplateID: templateID,
+		Subject: 		r.GetSubject(),
+		HTMLBody: 	r.GetHtmlBody(),
+		TextBody: 	r.GetTextBody(),
+	})
+	if err != nil {
+		e.logger.Errorf("templateUC.UpdateTemplate: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "templateUC.UpdateTemplate: %v", err)
+	}
+
+	return &emailService.UpdateTemplateResponse{Template: e.convertTemplateToProto(updated)}, nil
+}
+
+// Delete template
+func (e *EmailMicroservice) DeleteTemplate(
+	ctx context.Context,
+	r *emailService.DeleteTemplateRequest) (*emailService.DeleteTemplateResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.DeleteTemplate")
+	defer span.Finish()
+
+	templateID, err := uuid.Parse(r.GetTemplateId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	if err := e.templateUC.DeleteTemplate(ctx, templateID); err != nil {
+		e.logger.Errorf("templateUC.DeleteTemplate: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "templateUC.DeleteTemplate: %v", err)
+	}
+
+	return &emailService.DeleteTemplateResponse{Status: "Ok"}, nil
+}
+
+// List templates
+func (e *EmailMicroservice) ListTemplates(
+	ctx context.Context,
+	r *emailService.ListTemplatesRequest) (*emailService.ListTemplatesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ListTemplates")
+	defer span.Finish()
+
+	templates, err := e.templateUC.ListTemplates(ctx, &utils.PaginationQuery{
+		Size: r.GetSize(),
+		Page: r.GetPage(),
+	})
+	if err != nil {
+		e.logger.Errorf("templateUC.ListTemplates: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "templateUC.ListTemplates: %v", err)
+	}
+
+	protoTemplates := make([]*emailService.Template, 0, len(templates.Templates))
+	for _, t := range templates.Templates {
+		protoTemplates = append(protoTemplates, e.convertTemplateToProto(t))
+	}
+
+	return &emailService.ListTemplatesResponse{
+		Templates: 	protoTemplates,
+		TotalPages: templates.TotalPages,
+		TotalCount: templates.TotalCount,
+		HasMore: 		templates.HasMore,
+		Page: 			templates.Page,
+		Size: 			templates.Size,
+	}, nil
+}
+
+func (e *EmailMicroservice) convertTemplateToProto(t *models.Template) *emailService.Template {
+	return &emailService.Template{
+		TemplateId: t.TemplateID.String(),
+		Version: 		int32(t.Version),
+		Name: 			t.Name,
+		Subject: 		t.Subject,
+		HtmlBody: 	t.HTMLBody,
+		TextBody: 	t.TextBody,
+		CreatedAt: 	timestamppb.New(t.CreatedAt),
+	}
+}
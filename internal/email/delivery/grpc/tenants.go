@@ -0,0 +1,204 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Create tenant
+func (e *EmailMicroservice) CreateTenant(
+	ctx context.Context,
+	r *emailService.CreateTenantRequest) (*emailService.CreateTenantResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.CreateTenant")
+	defer span.Finish()
+
+	created, apiKey, err := e.tenantUC.CreateTenant(ctx, &models.Tenant{
+		Name: 			r.GetName(),
+		From: 			r.GetFrom(),
+		Providers: 	e.convertTenantProvidersFromProto(r.GetProviders()),
+		DailyQuota: 	r.GetDailyQuota(),
+		MonthlyQuota: r.GetMonthlyQuota(),
+		WebhookURL: 	r.GetWebhookUrl(),
+		WebhookSecret: r.GetWebhookSecret(),
+	})
+	if err != nil {
+		e.logger.Errorf("tenantUC.CreateTenant: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantUC.CreateTenant: %v", err)
+	}
+
+	return &emailService.CreateTenantResponse{
+		Tenant: 				e.convertTenantToProto(created),
+		ApiKey: 				apiKey,
+		WebhookSecret: 	created.WebhookSecret,
+	}, nil
+}
+
+// Update tenant
+func (e *EmailMicroservice) UpdateTenant(
+	ctx context.Context,
+	r *emailService.UpdateTenantRequest) (*emailService.UpdateTenantResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.UpdateTenant")
+	defer span.Finish()
+
+	tenantID, err := uuid.Parse(r.GetTenantId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	updated, err := e.tenantUC.UpdateTenant(ctx, &models.Tenant{
+		TenantID: 	tenantID,
+		Name: 			r.GetName(),
+		From: 			r.GetFrom(),
+		Providers: 	e.convertTenantProvidersFromProto(r.GetProviders()),
+		Disabled: 	r.GetDisabled(),
+		DailyQuota: 	r.GetDailyQuota(),
+		MonthlyQuota: r.GetMonthlyQuota(),
+		WebhookURL: 	r.GetWebhookUrl(),
+		WebhookSecret: r.GetWebhookSecret(),
+	})
+	if err != nil {
+		e.logger.Errorf("tenantUC.UpdateTenant: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantUC.UpdateTenant: %v", err)
+	}
+
+	return &emailService.UpdateTenantResponse{Tenant: e.convertTenantToProto(updated)}, nil
+}
+
+// Get tenant
+func (e *EmailMicroservice) GetTenant(
+	ctx context.Context,
+	r *emailService.GetTenantRequest) (*emailService.GetTenantResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetTenant")
+	defer span.Finish()
+
+	tenantID, err := uuid.Parse(r.GetTenantId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	t, err := e.tenantUC.GetTenant(ctx, tenantID)
+	if err != nil {
+		e.logger.Errorf("tenantUC.GetTenant: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantUC.GetTenant: %v", err)
+	}
+
+	return &emailService.GetTenantResponse{Tenant: e.convertTenantToProto(t)}, nil
+}
+
+// List tenants
+func (e *EmailMicroservice) ListTenants(
+	ctx context.Context,
+	r *emailService.ListTenantsRequest) (*emailService.ListTenantsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.ListTenants")
+	defer span.Finish()
+
+	tenants, err := e.tenantUC.ListTenants(ctx, &utils.PaginationQuery{
+		Size: r.GetSize(),
+		Page: r.GetPage(),
+	})
+	if err != nil {
+		e.logger.Errorf("tenantUC.ListTenants: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantUC.ListTenants: %v", err)
+	}
+
+	protoTenants := make([]*emailService.Tenant, 0, len(tenants.Tenants))
+	for _, t := range tenants.Tenants {
+		protoTenants = append(protoTenants, e.convertTenantToProto(t))
+	}
+
+	return &emailService.ListTenantsResponse{
+		Tenants: 		protoTenants,
+		TotalPages: tenants.TotalPages,
+		TotalCount: tenants.TotalCount,
+		HasMore: 		tenants.HasMore,
+		Page: 			tenants.Page,
+		Size: 			tenants.Size,
+	}, nil
+}
+
+// Rotate tenant API key
+func (e *EmailMicroservice) RotateTenantApiKey(
+	ctx context.Context,
+	r *emailService.RotateTenantApiKeyRequest) (*emailService.RotateTenantApiKeyResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.RotateTenantApiKey")
+	defer span.Finish()
+
+	tenantID, err := uuid.Parse(r.GetTenantId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	apiKey, err := e.tenantUC.RotateTenantAPIKey(ctx, tenantID)
+	if err != nil {
+		e.logger.Errorf("tenantUC.RotateTenantAPIKey: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantUC.RotateTenantAPIKey: %v", err)
+	}
+
+	return &emailService.RotateTenantApiKeyResponse{ApiKey: apiKey}, nil
+}
+
+func (e *EmailMicroservice) convertTenantProvidersFromProto(providers []*emailService.TenantProvider) models.TenantProviders {
+	tenantProviders := make(models.TenantProviders, 0, len(providers))
+	for _, p := range providers {
+		tenantProviders = append(tenantProviders, &models.TenantProvider{
+			Name: 		p.GetName(),
+			Backend: 	p.GetBackend(),
+			Host: 		p.GetHost(),
+			Port: 		int(p.GetPort()),
+			User: 		p.GetUser(),
+			Password: p.GetPassword(),
+			APIKey: 	p.GetApiKey(),
+			BaseURL: 	p.GetBaseUrl(),
+			Domain: 	p.GetDomain(),
+			From: 		p.GetFrom(),
+			WebhookKey: p.GetWebhookKey(),
+			Weight: 	int(p.GetWeight()),
+			Priority: int(p.GetPriority()),
+		})
+	}
+	return tenantProviders
+}
+
+// Provider and webhook secrets are never returned
+func (e *EmailMicroservice) convertTenantToProto(t *models.Tenant) *emailService.Tenant {
+	providers := make([]*emailService.TenantProvider, 0, len(t.Providers))
+	for _, p := range t.Providers {
+		providers = append(providers, &emailService.TenantProvider{
+			Name: 		p.Name,
+			Backend: 	p.Backend,
+			Host: 		p.Host,
+			Port: 		int32(p.Port),
+			User: 		p.User,
+			BaseUrl: 	p.BaseURL,
+			Domain: 	p.Domain,
+			From: 		p.From,
+			Weight: 	int32(p.Weight),
+			Priority: int32(p.Priority),
+		})
+	}
+
+	return &emailService.Tenant{
+		TenantId: 	t.TenantID.String(),
+		Name: 			t.Name,
+		From: 			t.From,
+		Providers: 	providers,
+		Disabled: 	t.Disabled,
+		CreatedAt: 	timestamppb.New(t.CreatedAt),
+		UpdatedAt: 	timestamppb.New(t.UpdatedAt),
+		DailyQuota: 	t.DailyQuota,
+		MonthlyQuota: t.MonthlyQuota,
+		WebhookUrl: 	t.WebhookURL,
+	}
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	emailService "rmq_service/internal/email/proto"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Get status webhook deliveries of the email
+func (e *EmailMicroservice) GetWebhookDeliveries(
+	ctx context.Context,
+	r *emailService.GetWebhookDeliveriesRequest) (*emailService.GetWebhookDeliveriesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailMicroservice.GetWebhookDeliveries")
+	defer span.Finish()
+
+	emailID, err := uuid.Parse(r.GetEmailId())
+	if err != nil {
+		e.logger.Errorf("uuid.Parse: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "uuid.Parse: %v", err)
+	}
+
+	deliveries, err := e.webhookUC.GetWebhookDeliveries(ctx, emailID)
+	if err != nil {
+		e.logger.Errorf("webhookUC.GetWebhookDeliveries: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "webhookUC.GetWebhookDeliveries: %v", err)
+	}
+
+	protoDeliveries := make([]*emailService.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		protoDeliveries = append(protoDeliveries, e.convertWebhookDeliveryToProto(d))
+	}
+
+	return &emailService.GetWebhookDeliveriesResponse{Deliveries: protoDeliveries}, nil
+}
+
+func (e *EmailMicroservice) convertWebhookDeliveryToProto(d *models.WebhookDelivery) *emailService.WebhookDelivery {
+	delivery := &emailService.WebhookDelivery{
+		DeliveryId: 		d.DeliveryID.String(),
+		EmailId: 				d.EmailID.String(),
+		Event: 					d.Event,
+		Url: 						d.URL,
+		Status: 				d.Status,
+		Attempts: 			int32(d.Attempts),
+		NextAttemptAt: 	timestamppb.New(d.NextAttemptAt),
+		ResponseCode: 	int32(d.ResponseCode),
+		LastError: 			d.LastError,
+		CreatedAt: 			timestamppb.New(d.CreatedAt),
+		Payload: 				string(d.Payload),
+	}
+
+	if d.DeliveredAt != nil {
+		delivery.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+
+	return delivery
+}
@@ -0,0 +1,156 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"rmq_service/config"
+	"rmq_service/internal/bounce"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/streadway/amqp"
+)
+
+var bounceReports = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "emails_bounce_reports_total",
+	Help: "The total number of consumed bounce reports by result",
+}, []string{"result"})
+
+// Bounce reports RabbitMQ Consumer, message body is the raw MIME report
+type BouncesConsumer struct {
+	amqpConn 	*amqp.Connection
+	cfg 			*config.Config
+	logger 		logger.Logger
+	bounceUC 	bounce.BouncesUseCase
+}
+
+// Bounces Consumer constructor
+func NewBouncesConsumer(
+	amqpConn *amqp.Connection,
+	cfg *config.Config,
+	logger logger.Logger,
+	bounceUC bounce.BouncesUseCase,
+) *BouncesConsumer {
+	return &BouncesConsumer{amqpConn: amqpConn, cfg: cfg, logger: logger, bounceUC: bounceUC}
+}
+
+// Creates channel to consume bounce reports
+func (c *BouncesConsumer) CreateChannel(exchangeName, queueName, bindingKey string) (*amqp.Channel, error) {
+	ch, err := c.amqpConn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Declaring bounces exchange: %s", exchangeName)
+	if err := ch.ExchangeDeclare(
+		exchangeName,
+		exchangeKind,
+		exchangeDurable,
+		exchangeAutoDelete,
+		exchangeInternal,
+		exchangeNoWait,
+		nil,
+	); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	queue, err := ch.QueueDeclare(
+		queueName,
+		queueDurable,
+		queueAutoDelete,
+		queueExclusive,
+		queueNoWait,
+		nil,
+	)
+	if err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	if err := ch.QueueBind(queue.Name, bindingKey, exchangeName, queueNoWait, nil); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	if err := ch.Qos(prefetchCount, prefetchSize, prefetchGlobal); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	c.logger.Infof("Bounces queue %s bound to exchange %s, binding key: %s", queue.Name, exchangeName, bindingKey)
+	return ch, nil
+}
+
+// Reports which are not reports or match no email are dropped, they would never succeed
+func (c *BouncesConsumer) worker(ctx context.Context, messages <-chan amqp.Delivery) {
+	for delivery := range messages {
+		span, ctx := opentracing.StartSpanFromContext(ctx, "BouncesConsumer.worker")
+
+		bounces, err := c.bounceUC.ProcessReport(ctx, delivery.Body)
+		switch {
+		case errors.Is(err, grpc_errors.ErrInvalidBounceReport), errors.Is(err, grpc_errors.ErrEmailNotMatched):
+			c.logger.Warnf("Drop bounce report, messageId: %s: %v", delivery.MessageId, err)
+			bounceReports.WithLabelValues("dropped").Inc()
+			c.ack(delivery)
+		case err != nil:
+			c.logger.Errorf("Failed to process bounce report: %v", err)
+			bounceReports.WithLabelValues("error").Inc()
+			if err := delivery.Nack(false, true); err != nil {
+				c.logger.Errorf("Error delivery.Nack: %v", err)
+			}
+		default:
+			c.logger.Infof("Bounce report processed, bounces: %d", len(bounces))
+			bounceReports.WithLabelValues("processed").Inc()
+			c.ack(delivery)
+		}
+		span.Finish()
+	}
+
+	c.logger.Info("Bounce deliveries channel closed")
+}
+
+func (c *BouncesConsumer) ack(delivery amqp.Delivery) {
+	if err := delivery.Ack(false); err != nil {
+		c.logger.Errorf("Failed to acknowledge the message: %v", err)
+	}
+}
+
+// Start bounce reports consumer
+func (c *BouncesConsumer) StartConsumer(
+	workerPoolSize int,
+	exchange, queueName, bindingKey, consumerTag string,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := c.CreateChannel(exchange, queueName, bindingKey)
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	deliveries, err := ch.Consume(
+		queueName,
+		consumerTag,
+		consumeAutoAck,
+		consumeExclusive,
+		consumeNoLocal,
+		consumeNoWait,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < workerPoolSize; i++ {
+		go c.worker(ctx, deliveries)
+	}
+
+	chanErr := <-ch.NotifyClose(make(chan *amqp.Error))
+	c.logger.Errorf("ch.NotifyClose(): %v", chanErr)
+	return chanErr
+}
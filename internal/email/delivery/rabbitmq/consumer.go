@@ -2,9 +2,14 @@ package rabbitmq
 
 import (
 	"context"
+	"errors"
 	"log"
+	"rmq_service/config"
 	"rmq_service/internal/email"
+	"rmq_service/internal/email/ratelimit"
+	"rmq_service/internal/models"
 	"rmq_service/pkg/logger"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
@@ -54,9 +59,17 @@ var (
 	})
 )
 
+// Emails queue arguments, priority queue lets transactional emails overtake the queued bulk ones.
+// Arguments of the existing queue can not be changed, so the priority queue has a new name
+// and the queue declared without priority is drained as the legacy queue.
+func emailsQueueArgs() amqp.Table {
+	return amqp.Table{"x-max-priority": int64(models.MaxEmailQueuePriority)}
+}
+
 // Images RabbitMQ Consumer
 type EmailsConsumer struct {
 	amqpConn 	*amqp.Connection
+	cfg 			*config.Config
 	logger 		logger.Logger
 	emailUC		email.EmailsUseCase
 }
@@ -64,10 +77,11 @@ type EmailsConsumer struct {
 // Images Consumer constructor
 func NewImagesConsumer(
 	amqpConn *amqp.Connection,
+	cfg *config.Config,
 	logger logger.Logger,
 	emailUC email.EmailsUseCase,
 ) *EmailsConsumer {
-	return &EmailsConsumer{amqpConn: amqpConn, logger: logger, emailUC: emailUC}
+	return &EmailsConsumer{amqpConn: amqpConn, cfg: cfg, logger: logger, emailUC: emailUC}
 }
 
 // Creates channel to consume messages
@@ -101,7 +115,7 @@ func (c *EmailsConsumer) CreateChannel(
 		queueAutoDelete,
 		queueExclusive,
 		queueNoWait,
-		nil,
+		emailsQueueArgs(),
 	)
 	if err != nil {
 		log.Fatalf("Consumer::QueueDeclare(): %v", err)
@@ -128,6 +142,12 @@ func (c *EmailsConsumer) CreateChannel(
 		log.Fatalf("Consumer::QueueBing(): %v", err)
 	}
 
+	c.logger.Infof("Declaring retry exchange: %s", c.cfg.RabbitMQ.RetryExchange)
+	if err := declareRetryTopology(ch, c.cfg.RabbitMQ); err != nil {
+		log.Fatalf("Consumer::declareRetryTopology(): %v", err)
+		return nil, err
+	}
+
 	c.logger.Infof("Queue bound to exchange, starting to consume from queue, consumerTag: %v", consumerTag)
 
 	err = ch.Qos(prefetchCount, prefetchSize, prefetchGlobal)
@@ -139,7 +159,7 @@ func (c *EmailsConsumer) CreateChannel(
 	return ch, nil
 }
 
-func (c *EmailsConsumer) worker(ctx context.Context, messages <-chan amqp.Delivery) {
+func (c *EmailsConsumer) worker(ctx context.Context, ch *amqp.Channel, messages <-chan amqp.Delivery) {
 	for delivery := range messages {
 		span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsConsumer.worker")
 
@@ -147,14 +167,18 @@ func (c *EmailsConsumer) worker(ctx context.Context, messages <-chan amqp.Delive
 
 		incomingMessages.Inc()
 
-		err := c.emailUC.SendEmails(ctx, delivery.Body)
-		if err != nil {
-			if err := delivery.Reject(false); err != nil {
-				c.logger.Errorf("Error delivery.Reject: %v", err)
-			}
-
+		err := c.emailUC.SendEmails(ctx, delivery.Body, delivery.Redelivered)
+		var limitErr *ratelimit.LimitExceededError
+		if errors.As(err, &limitErr) {
+			c.throttle(ch, delivery, limitErr.Delay)
+		} else if errors.Is(err, email.ErrPermanentFailure) {
+			c.logger.Errorf("Failed to process delivery permanently: %v", err)
+			errorMessages.Inc()
+			c.park(ctx, ch, delivery, err)
+		} else if err != nil {
 			c.logger.Errorf("Failed to process delivery: %v", err)
 			errorMessages.Inc()
+			c.retry(ctx, ch, delivery, err)
 		} else {
 			if err = delivery.Ack(false); err != nil {
 				c.logger.Errorf("Failed to acknowledge the message: %v", err)
@@ -166,6 +190,105 @@ func (c *EmailsConsumer) worker(ctx context.Context, messages <-chan amqp.Delive
 	c.logger.Info("Deliveries channel closed")
 }
 
+// Move rate limited delivery to throttle queue
+func (c *EmailsConsumer) throttle(ch *amqp.Channel, delivery amqp.Delivery, delay time.Duration) {
+	if err := throttle(ch, c.cfg.RabbitMQ, delivery, delay); err != nil {
+		c.logger.Errorf("throttle: %v", err)
+		if err := delivery.Nack(false, true); err != nil {
+			c.logger.Errorf("Error delivery.Nack: %v", err)
+		}
+		return
+	}
+
+	if err := delivery.Ack(false); err != nil {
+		c.logger.Errorf("Failed to acknowledge the message: %v", err)
+	}
+}
+
+// Move failed delivery to retry or parking queue
+func (c *EmailsConsumer) retry(ctx context.Context, ch *amqp.Channel, delivery amqp.Delivery, cause error) {
+	parked, err := retryOrPark(ch, c.cfg.RabbitMQ, delivery, cause)
+	if err != nil {
+		c.logger.Errorf("retryOrPark: %v", err)
+		if err := delivery.Nack(false, true); err != nil {
+			c.logger.Errorf("Error delivery.Nack: %v", err)
+		}
+		return
+	}
+
+	if parked {
+		c.logger.Warnf("Delivery parked, messageId: %s, reason: %v", delivery.MessageId, cause)
+		c.notifyParked(ctx, delivery, cause)
+	}
+
+	if err := delivery.Ack(false); err != nil {
+		c.logger.Errorf("Failed to acknowledge the message: %v", err)
+	}
+}
+
+// Move permanently failed delivery to parking queue without retries
+func (c *EmailsConsumer) park(ctx context.Context, ch *amqp.Channel, delivery amqp.Delivery, cause error) {
+	if err := park(ch, c.cfg.RabbitMQ, delivery, cause, deliveryAttempts(c.cfg.RabbitMQ, delivery.Headers)); err != nil {
+		c.logger.Errorf("park: %v", err)
+		if err := delivery.Nack(false, true); err != nil {
+			c.logger.Errorf("Error delivery.Nack: %v", err)
+		}
+		return
+	}
+
+	c.logger.Warnf("Delivery parked, messageId: %s, reason: %v", delivery.MessageId, cause)
+	c.notifyParked(ctx, delivery, cause)
+	if err := delivery.Ack(false); err != nil {
+		c.logger.Errorf("Failed to acknowledge the message: %v", err)
+	}
+}
+
+// Post failure of the parked email to its status webhook, the delivery is parked either way
+func (c *EmailsConsumer) notifyParked(ctx context.Context, delivery amqp.Delivery, cause error) {
+	if err := c.emailUC.NotifyEmailParked(ctx, delivery.Body, cause); err != nil {
+		c.logger.Errorf("emailUC.NotifyEmailParked: %v", err)
+	}
+}
+
+// Unbind the legacy queue, so new messages are routed to the priority queue only.
+// Returns false when no legacy queue is configured or it does not exist.
+func (c *EmailsConsumer) unbindLegacyQueue(exchangeName, bindingKey string) (bool, error) {
+	legacyQueue := c.cfg.RabbitMQ.LegacyQueue
+	if legacyQueue == "" {
+		return false, nil
+	}
+
+	// failed passive declare closes the channel, so the queue is checked on its own one
+	ch, err := c.amqpConn.Channel()
+	if err != nil {
+		return false, err
+	}
+	defer ch.Close()
+
+	queue, err := ch.QueueDeclarePassive(
+		legacyQueue,
+		queueDurable,
+		queueAutoDelete,
+		queueExclusive,
+		queueNoWait,
+		nil,
+	)
+	if err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := ch.QueueUnbind(legacyQueue, bindingKey, exchangeName, nil); err != nil {
+		return false, err
+	}
+
+	c.logger.Infof("Legacy queue %s unbound, draining %d messages", legacyQueue, queue.Messages)
+	return true, nil
+}
+
 // Start new rabbitmq consumer
 func (c *EmailsConsumer) StartConsumer(
 	workerPoolSize int,
@@ -196,7 +319,29 @@ func (c *EmailsConsumer) StartConsumer(
 	}
 
 	for i := 0; i < workerPoolSize; i++ {
-		go c.worker(ctx, deliveries)
+		go c.worker(ctx, ch, deliveries)
+	}
+
+	legacy, err := c.unbindLegacyQueue(exchange, bindingKey)
+	if err != nil {
+		log.Fatalf("Consumer::StartConsumer()::unbindLegacyQueue(): %v", err)
+		return err
+	}
+	if legacy {
+		legacyDeliveries, err := ch.Consume(
+			c.cfg.RabbitMQ.LegacyQueue,
+			consumerTag+"-legacy",
+			consumeAutoAck,
+			consumeExclusive,
+			consumeNoLocal,
+			consumeNoWait,
+			nil,
+		)
+		if err != nil {
+			log.Fatalf("Consumer::StartConsumer()::Consume(): %v", err)
+			return err
+		}
+		go c.worker(ctx, ch, legacyDeliveries)
 	}
 
 	chanErr := <-ch.NotifyClose(make(chan *amqp.Error))
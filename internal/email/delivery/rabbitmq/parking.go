@@ -0,0 +1,173 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rmq_service/internal/models"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/streadway/amqp"
+)
+
+const (
+	parkingGetAutoAck 	= false
+	parkingNackMultiple = false
+	parkingRequeue 			= true
+)
+
+// Parked message handler, returns true if message must be removed from parking queue
+type parkedHandler func(delivery amqp.Delivery, parked *models.ParkedEmail) (remove bool, err error)
+
+// List parked emails
+func (p *EmailsPublisher) ListParked(ctx context.Context, filter *models.ParkedEmailsFilter) ([]*models.ParkedEmail, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsPublisher.ListParked")
+	defer span.Finish()
+
+	parked := make([]*models.ParkedEmail, 0)
+	err := p.scanParked(ctx, filter, func(_ amqp.Delivery, email *models.ParkedEmail) (bool, error) {
+		parked = append(parked, email)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parked, nil
+}
+
+// Replay parked emails to the main exchange
+func (p *EmailsPublisher) ReplayParked(ctx context.Context, filter *models.ParkedEmailsFilter) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsPublisher.ReplayParked")
+	defer span.Finish()
+
+	replayed := 0
+	err := p.scanParked(ctx, filter, func(delivery amqp.Delivery, _ *models.ParkedEmail) (bool, error) {
+		msg := republishing(delivery)
+		// replayed message starts over with a fresh attempts count
+		for _, header := range []string{deathHeader, failureReasonHeader, attemptsHeader, failedAtHeader} {
+			delete(msg.Headers, header)
+		}
+
+		if err := p.amqpChan.Publish(
+			p.cfg.RabbitMQ.Exchange,
+			p.cfg.RabbitMQ.RoutingKey,
+			publishMandatory,
+			publishImmediate,
+			msg,
+		); err != nil {
+			return false, err
+		}
+
+		publishedMessages.Inc()
+		replayed++
+		return true, nil
+	})
+
+	return replayed, err
+}
+
+// Remove parked emails, removed emails are returned
+func (p *EmailsPublisher) PurgeParked(ctx context.Context, filter *models.ParkedEmailsFilter) ([]*models.ParkedEmail, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsPublisher.PurgeParked")
+	defer span.Finish()
+
+	purged := make([]*models.ParkedEmail, 0)
+	err := p.scanParked(ctx, filter, func(_ amqp.Delivery, email *models.ParkedEmail) (bool, error) {
+		purged = append(purged, email)
+		return true, nil
+	})
+
+	return purged, err
+}
+
+// Walk through parking queue once, matched messages are passed to handler.
+// Kept messages stay unacked until the end of the walk, so they are not fetched twice,
+// and are requeued to their original position afterwards.
+func (p *EmailsPublisher) scanParked(ctx context.Context, filter *models.ParkedEmailsFilter, handle parkedHandler) error {
+	p.parkingMu.Lock()
+	defer p.parkingMu.Unlock()
+
+	queue, err := p.amqpChan.QueueInspect(p.cfg.RabbitMQ.ParkingQueue)
+	if err != nil {
+		return fmt.Errorf("QueueInspect: %w", err)
+	}
+
+	kept := make([]amqp.Delivery, 0, queue.Messages)
+	defer func() {
+		for _, delivery := range kept {
+			if err := delivery.Nack(parkingNackMultiple, parkingRequeue); err != nil {
+				p.logger.Errorf("EmailsPublisher::scanParked() Nack: %v", err)
+			}
+		}
+	}()
+
+	matched := 0
+	for i := 0; i < queue.Messages; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if filter != nil && filter.Limit > 0 && matched >= filter.Limit {
+			return nil
+		}
+
+		delivery, ok, err := p.amqpChan.Get(p.cfg.RabbitMQ.ParkingQueue, parkingGetAutoAck)
+		if err != nil {
+			return fmt.Errorf("Get: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		parked := parkedFromDelivery(delivery)
+		if !filter.Match(parked) {
+			kept = append(kept, delivery)
+			continue
+		}
+		matched++
+
+		remove, err := handle(delivery, parked)
+		if err != nil {
+			kept = append(kept, delivery)
+			return err
+		}
+
+		if !remove {
+			kept = append(kept, delivery)
+			continue
+		}
+
+		if err := delivery.Ack(false); err != nil {
+			return fmt.Errorf("Ack: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Read parked email from delivery
+func parkedFromDelivery(delivery amqp.Delivery) *models.ParkedEmail {
+	parked := &models.ParkedEmail{MessageID: delivery.MessageId}
+
+	mail := &models.Email{}
+	if err := json.Unmarshal(delivery.Body, mail); err == nil {
+		parked.Email = mail
+	}
+
+	parked.FailureReason, _ = delivery.Headers[failureReasonHeader].(string)
+
+	switch attempts := delivery.Headers[attemptsHeader].(type) {
+	case int64:
+		parked.Attempts = attempts
+	case int32:
+		parked.Attempts = int64(attempts)
+	}
+
+	if failedAt, ok := delivery.Headers[failedAtHeader].(string); ok {
+		parked.FailedAt, _ = time.Parse(time.RFC3339, failedAt)
+	}
+
+	return parked
+}
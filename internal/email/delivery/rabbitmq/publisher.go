@@ -1,18 +1,28 @@
 package rabbitmq
 
 import (
+	"encoding/json"
 	"log"
 	"rmq_service/config"
+	"rmq_service/internal/models"
 	"rmq_service/pkg/logger"
+	"rmq_service/pkg/mime_types"
 	"rmq_service/pkg/rabbitmq"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/streadway/amqp"
 )
 
+const (
+	eventsExchangeKind 	= "topic"
+	schemaVersionHeader = "x-schema-version"
+)
+
 var (
 	publishedMessages = promauto.NewCounter(
 		prometheus.CounterOpts{
@@ -20,13 +30,26 @@ var (
 			Help: "The total number of published RabbitMQ messages",
 		},
 	)
+
+	publishedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "email_published_lifecycle_events_total",
+		Help: "The total number of published email lifecycle events by type",
+	}, []string{"type"})
 )
 
-// Emils rabbitmq publisher
+// Emils rabbitmq publisher. Lifecycle events are published on their own channel,
+// so a channel error of the events exchange never closes the channel of the emails.
 type EmailsPublisher struct {
+	mqConn 		*amqp.Connection
 	amqpChan 	*amqp.Channel
 	cfg 			*config.Config
 	logger 		logger.Logger
+	parkingMu sync.Mutex
+
+	eventsMu 		sync.Mutex
+	eventsChan 	*amqp.Channel
+	// closed when the broker closes the events channel, it is opened again by the next event
+	eventsClosed chan *amqp.Error
 }
 
 // Emails rabbitmq publisher constructor
@@ -42,7 +65,52 @@ func NewEmailsPublisher(cfg *config.Config, logger logger.Logger) (*EmailsPublis
 		return nil, err
 	}
 
-	return &EmailsPublisher{amqpChan: amqpChan, cfg: cfg, logger: logger}, nil
+	publisher := &EmailsPublisher{mqConn: mqConn, amqpChan: amqpChan, cfg: cfg, logger: logger}
+	if cfg.RabbitMQ.EventsExchange != "" {
+		if _, err := publisher.getEventsChan(); err != nil {
+			return nil, errors.Wrap(err, "getEventsChan")
+		}
+	}
+
+	return publisher, nil
+}
+
+// Events channel, the channel closed by the broker is opened again with the topic exchange of the lifecycle
+// events declared on it, consumers bind their queues by event type. Must be called with eventsMu held
+// or before the publisher is shared.
+func (p *EmailsPublisher) getEventsChan() (*amqp.Channel, error) {
+	if p.eventsChan != nil {
+		select {
+		case err := <-p.eventsClosed:
+			p.logger.Warnf("Events channel closed: %v", err)
+			p.eventsChan = nil
+		default:
+			return p.eventsChan, nil
+		}
+	}
+
+	ch, err := p.mqConn.Channel()
+	if err != nil {
+		return nil, errors.Wrap(err, "mqConn.Channel")
+	}
+
+	p.logger.Infof("Declaring events exchange: %s", p.cfg.RabbitMQ.EventsExchange)
+	if err := ch.ExchangeDeclare(
+		p.cfg.RabbitMQ.EventsExchange,
+		eventsExchangeKind,
+		exchangeDurable,
+		exchangeAutoDelete,
+		exchangeInternal,
+		exchangeNoWait,
+		nil,
+	); err != nil {
+		ch.Close()
+		return nil, errors.Wrap(err, "ExchangeDeclare")
+	}
+
+	p.eventsChan = ch
+	p.eventsClosed = ch.NotifyClose(make(chan *amqp.Error, 1))
+	return ch, nil
 }
 
 // Create exchange and queue
@@ -69,7 +137,7 @@ func (p *EmailsPublisher) SetupExchangeAndQueue(exchange, queueName, bindingKey,
 		queueAutoDelete,
 		queueExclusive,
 		queueNoWait,
-		nil,
+		emailsQueueArgs(),
 	)
 
 	if err != nil {
@@ -99,22 +167,40 @@ func (p *EmailsPublisher) SetupExchangeAndQueue(exchange, queueName, bindingKey,
 		return err
 	}
 
+	p.logger.Infof("Declaring retry exchange: %s", p.cfg.RabbitMQ.RetryExchange)
+	if err := declareRetryTopology(p.amqpChan, p.cfg.RabbitMQ); err != nil {
+		log.Fatalf("declareRetryTopology(): %s", err)
+		return err
+	}
+
 	p.logger.Infof("Queue bound to exchange, starting to consume from queue, consumerTag: %v", consumerTag)
 
 	return nil
 }
 
-// Close messages chan
+// Close messages and events chans
 func (p *EmailsPublisher) CloseChan() {
 	if err := p.amqpChan.Close(); err != nil {
 		p.logger.Errorf("EmailsPublisher::CloseChan(): %v", err)
 	}
+
+	p.eventsMu.Lock()
+	defer p.eventsMu.Unlock()
+	if p.eventsChan != nil {
+		if err := p.eventsChan.Close(); err != nil {
+			p.logger.Errorf("EmailsPublisher::CloseChan() events: %v", err)
+		}
+	}
 }
 
-// Publish message
-func (p *EmailsPublisher) Publish(body []byte, contentType string) error {
+// Publish message with the queue priority, random message id is used when messageID is empty
+func (p *EmailsPublisher) Publish(body []byte, contentType, messageID string, priority uint8) error {
 	p.logger.Infof("Pulishing message Exchange: %s, RoutingKey: %s", p.cfg.RabbitMQ.Exchange, p.cfg.RabbitMQ.RoutingKey)
-	
+
+	if messageID == "" {
+		messageID = uuid.New().String()
+	}
+
 	if err := p.amqpChan.Publish(
 		p.cfg.RabbitMQ.Exchange,
 		p.cfg.RabbitMQ.RoutingKey,
@@ -123,15 +209,57 @@ func (p *EmailsPublisher) Publish(body []byte, contentType string) error {
 		amqp.Publishing{
 			ContentType: contentType,
 			DeliveryMode: amqp.Persistent,
-			MessageId: uuid.New().String(),
+			Priority: priority,
+			MessageId: messageID,
 			Timestamp: time.Now(),
 			Body: body,
 		},
 	); err != nil {
-		log.Fatalf("Publish(): %s", err)
-		return err
+		return errors.Wrap(err, "amqpChan.Publish")
 	}
 
 	publishedMessages.Inc()
 	return nil
 }
+
+// Publish lifecycle event with its type as the routing key on the events channel,
+// events are dropped when the exchange is not configured
+func (p *EmailsPublisher) PublishEvent(event *models.LifecycleEvent) error {
+	if p.cfg.RabbitMQ.EventsExchange == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	p.eventsMu.Lock()
+	defer p.eventsMu.Unlock()
+
+	ch, err := p.getEventsChan()
+	if err != nil {
+		return errors.Wrap(err, "getEventsChan")
+	}
+
+	if err := ch.Publish(
+		p.cfg.RabbitMQ.EventsExchange,
+		event.Type,
+		publishMandatory,
+		publishImmediate,
+		amqp.Publishing{
+			Headers: 			amqp.Table{schemaVersionHeader: int32(event.SchemaVersion)},
+			ContentType: 	mime_types.MIMEApplicationJSON,
+			DeliveryMode: amqp.Persistent,
+			MessageId: 		event.EventID.String(),
+			Timestamp: 		event.OccurredAt,
+			Type: 				event.Type,
+			Body: 				body,
+		},
+	); err != nil {
+		return errors.Wrap(err, "eventsChan.Publish")
+	}
+
+	publishedEvents.WithLabelValues(event.Type).Inc()
+	return nil
+}
@@ -0,0 +1,266 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"rmq_service/config"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/streadway/amqp"
+)
+
+const (
+	deathHeader 					= "x-death"
+	failureReasonHeader 	= "x-failure-reason"
+	attemptsHeader 				= "x-attempts"
+	failedAtHeader 				= "x-failed-at"
+
+	defaultRetryBaseDelay = 5 * time.Second
+	defaultMaxAttempts 		= 5
+	defaultThrottleBaseDelay 	= time.Second
+	defaultThrottleTiers 			= 7
+)
+
+var (
+	throttledMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "emails_throttled_rabbitmq_messages_total",
+		Help: "The total number of rate limited RabbitMQ messages sent to throttle queue",
+	})
+
+	retriedMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "emails_retried_rabbitmq_messages_total",
+		Help: "The total number of RabbitMQ messages sent to retry delay queues",
+	})
+
+	parkedMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "emails_parked_rabbitmq_messages_total",
+		Help: "The total number of RabbitMQ messages moved to parking queue",
+	})
+)
+
+// Delay queue, expired messages go back to the main exchange
+type retryTier struct {
+	queue string
+	delay time.Duration
+}
+
+// Get delay queues of the kind, every next tier doubles the delay
+func delayTiers(cfg config.RabbitMQ, kind string, baseDelay time.Duration, tiersCount int) []retryTier {
+	tiers := make([]retryTier, 0, tiersCount)
+	for i := 0; i < tiersCount; i++ {
+		delay := baseDelay << i
+		tiers = append(tiers, retryTier{
+			// delay is part of the name, so changed tiers never clash with already declared queues
+			queue: fmt.Sprintf("%s.%s.%s", cfg.Queue, kind, delay),
+			delay: delay,
+		})
+	}
+	return tiers
+}
+
+// Get retry delay queues
+func retryTiers(cfg config.RabbitMQ) []retryTier {
+	baseDelay := cfg.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+
+	tiersCount := cfg.RetryTiers
+	if tiersCount <= 0 {
+		tiersCount = 1
+	}
+	return delayTiers(cfg, "retry", baseDelay, tiersCount)
+}
+
+// Get throttle delay queues of rate limited messages.
+// They are not retry tiers, so throttled deliveries do not count as failed attempts.
+// Every queue has a fixed delay, so a long delayed message never holds back shorter ones.
+func throttleTiers(cfg config.RabbitMQ) []retryTier {
+	baseDelay := cfg.ThrottleBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultThrottleBaseDelay
+	}
+
+	tiersCount := cfg.ThrottleTiers
+	if tiersCount <= 0 {
+		tiersCount = defaultThrottleTiers
+	}
+	return delayTiers(cfg, "throttle", baseDelay, tiersCount)
+}
+
+// Get throttle tier of the shortest delay not less than the given one, the longest tier otherwise
+func throttleTierFor(cfg config.RabbitMQ, delay time.Duration) retryTier {
+	tiers := throttleTiers(cfg)
+	for _, tier := range tiers {
+		if tier.delay >= delay {
+			return tier
+		}
+	}
+	return tiers[len(tiers)-1]
+}
+
+// Get retry tier for the given attempt
+func retryTierFor(cfg config.RabbitMQ, attempt int) retryTier {
+	tiers := retryTiers(cfg)
+	if attempt > len(tiers) {
+		attempt = len(tiers)
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	return tiers[attempt-1]
+}
+
+// Get max delivery attempts
+func maxAttempts(cfg config.RabbitMQ) int {
+	if cfg.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return cfg.MaxAttempts
+}
+
+// Count delivery attempts made so far by the x-death header of retry queues
+func deliveryAttempts(cfg config.RabbitMQ, headers amqp.Table) int {
+	deaths, ok := headers[deathHeader].([]interface{})
+	if !ok {
+		return 1
+	}
+
+	prefix := cfg.Queue + ".retry."
+	attempts := 1
+	for _, d := range deaths {
+		death, ok := d.(amqp.Table)
+		if !ok {
+			continue
+		}
+
+		queue, _ := death["queue"].(string)
+		if !strings.HasPrefix(queue, prefix) {
+			continue
+		}
+
+		switch count := death["count"].(type) {
+		case int64:
+			attempts += int(count)
+		case int32:
+			attempts += int(count)
+		}
+	}
+	return attempts
+}
+
+// Declare retry exchange, delay queues and parking queue
+func declareRetryTopology(ch *amqp.Channel, cfg config.RabbitMQ) error {
+	if err := ch.ExchangeDeclare(
+		cfg.RetryExchange,
+		exchangeKind,
+		exchangeDurable,
+		exchangeAutoDelete,
+		exchangeInternal,
+		exchangeNoWait,
+		nil,
+	); err != nil {
+		return fmt.Errorf("ExchangeDeclare(%s): %w", cfg.RetryExchange, err)
+	}
+
+	for _, tier := range append(retryTiers(cfg), throttleTiers(cfg)...) {
+		if _, err := ch.QueueDeclare(
+			tier.queue,
+			queueDurable,
+			queueAutoDelete,
+			queueExclusive,
+			queueNoWait,
+			amqp.Table{
+				"x-message-ttl": 						tier.delay.Milliseconds(),
+				"x-dead-letter-exchange": 		cfg.Exchange,
+				"x-dead-letter-routing-key": 	cfg.RoutingKey,
+			},
+		); err != nil {
+			return fmt.Errorf("QueueDeclare(%s): %w", tier.queue, err)
+		}
+
+		if err := ch.QueueBind(tier.queue, tier.queue, cfg.RetryExchange, queueNoWait, nil); err != nil {
+			return fmt.Errorf("QueueBind(%s): %w", tier.queue, err)
+		}
+	}
+
+	if _, err := ch.QueueDeclare(
+		cfg.ParkingQueue,
+		queueDurable,
+		queueAutoDelete,
+		queueExclusive,
+		queueNoWait,
+		nil,
+	); err != nil {
+		return fmt.Errorf("QueueDeclare(%s): %w", cfg.ParkingQueue, err)
+	}
+
+	if err := ch.QueueBind(cfg.ParkingQueue, cfg.ParkingQueue, cfg.RetryExchange, queueNoWait, nil); err != nil {
+		return fmt.Errorf("QueueBind(%s): %w", cfg.ParkingQueue, err)
+	}
+
+	return nil
+}
+
+// Copy delivery into a new publishing keeping its headers
+func republishing(delivery amqp.Delivery) amqp.Publishing {
+	headers := amqp.Table{}
+	for k, v := range delivery.Headers {
+		headers[k] = v
+	}
+
+	return amqp.Publishing{
+		Headers: 				headers,
+		ContentType: 		delivery.ContentType,
+		DeliveryMode: 	amqp.Persistent,
+		Priority: 			delivery.Priority,
+		MessageId: 			delivery.MessageId,
+		Timestamp: 			delivery.Timestamp,
+		Type: 					delivery.Type,
+		Body: 					delivery.Body,
+	}
+}
+
+// Send failed delivery to the next retry tier or park it after the last attempt
+func retryOrPark(ch *amqp.Channel, cfg config.RabbitMQ, delivery amqp.Delivery, cause error) (parked bool, err error) {
+	attempt := deliveryAttempts(cfg, delivery.Headers)
+	if attempt >= maxAttempts(cfg) {
+		if err := park(ch, cfg, delivery, cause, attempt); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	tier := retryTierFor(cfg, attempt)
+	if err := ch.Publish(cfg.RetryExchange, tier.queue, publishMandatory, publishImmediate, republishing(delivery)); err != nil {
+		return false, err
+	}
+	retriedMessages.Inc()
+	return false, nil
+}
+
+// Send failed delivery to the parking queue with the failure reason
+func park(ch *amqp.Channel, cfg config.RabbitMQ, delivery amqp.Delivery, cause error, attempt int) error {
+	msg := republishing(delivery)
+	msg.Headers[failureReasonHeader] = cause.Error()
+	msg.Headers[attemptsHeader] = int64(attempt)
+	msg.Headers[failedAtHeader] = time.Now().UTC().Format(time.RFC3339)
+
+	if err := ch.Publish(cfg.RetryExchange, cfg.ParkingQueue, publishMandatory, publishImmediate, msg); err != nil {
+		return err
+	}
+	parkedMessages.Inc()
+	return nil
+}
+
+// Delay rate limited delivery in the throttle queue
+func throttle(ch *amqp.Channel, cfg config.RabbitMQ, delivery amqp.Delivery, delay time.Duration) error {
+	tier := throttleTierFor(cfg, delay)
+	if err := ch.Publish(cfg.RetryExchange, tier.queue, publishMandatory, publishImmediate, republishing(delivery)); err != nil {
+		return err
+	}
+	throttledMessages.Inc()
+	return nil
+}
@@ -0,0 +1,48 @@
+package rabbitmq
+
+import (
+	"testing"
+	"time"
+
+	"rmq_service/config"
+)
+
+func TestThrottleTierFor(t *testing.T) {
+	cfg := config.RabbitMQ{Queue: "emails-queue", ThrottleBaseDelay: time.Second, ThrottleTiers: 4}
+
+	tests := []struct {
+		delay time.Duration
+		queue string
+	}{
+		{0, "emails-queue.throttle.1s"},
+		{300 * time.Millisecond, "emails-queue.throttle.1s"},
+		{time.Second, "emails-queue.throttle.1s"},
+		{1500 * time.Millisecond, "emails-queue.throttle.2s"},
+		{5 * time.Second, "emails-queue.throttle.8s"},
+		{time.Minute, "emails-queue.throttle.8s"},
+	}
+
+	for _, tt := range tests {
+		if got := throttleTierFor(cfg, tt.delay).queue; got != tt.queue {
+			t.Errorf("throttleTierFor(%s) = %s, want %s", tt.delay, got, tt.queue)
+		}
+	}
+}
+
+func TestRetryTiers(t *testing.T) {
+	tiers := retryTiers(config.RabbitMQ{Queue: "emails-queue", RetryBaseDelay: 5 * time.Second, RetryTiers: 3})
+
+	want := []retryTier{
+		{queue: "emails-queue.retry.5s", delay: 5 * time.Second},
+		{queue: "emails-queue.retry.10s", delay: 10 * time.Second},
+		{queue: "emails-queue.retry.20s", delay: 20 * time.Second},
+	}
+	if len(tiers) != len(want) {
+		t.Fatalf("tiers %+v, want %+v", tiers, want)
+	}
+	for i := range want {
+		if tiers[i] != want[i] {
+			t.Errorf("tier %d = %+v, want %+v", i, tiers[i], want[i])
+		}
+	}
+}
@@ -1,9 +1,18 @@
 //go:generate mockgen -source email_rabbitmq.go -destination mock/email_rabbitmq.go -package mock
 package email
 
+import (
+	"context"
+	"rmq_service/internal/models"
+)
+
 // Emails Publisher interface
 type EmailsPublisher interface {
-	Publish(body []byte, contentType string) error
+	Publish(body []byte, contentType, messageID string, priority uint8) error
+	PublishEvent(event *models.LifecycleEvent) error
+	ListParked(ctx context.Context, filter *models.ParkedEmailsFilter) ([]*models.ParkedEmail, error)
+	ReplayParked(ctx context.Context, filter *models.ParkedEmailsFilter) (int, error)
+	PurgeParked(ctx context.Context, filter *models.ParkedEmailsFilter) ([]*models.ParkedEmail, error)
 }
 
 // Emails Consumer interface
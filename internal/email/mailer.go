@@ -3,9 +3,13 @@ package email
 
 import (
 	"context"
+	"errors"
 	"rmq_service/internal/models"
 )
 
+// Sending failure which fails again on retry, e.g. rejected recipient or invalid provider API key
+var ErrPermanentFailure = errors.New("permanent sending failure")
+
 // Mailer interface
 type Mailer interface {
 	Send(context.Context, *models.Email) error
@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"rmq_service/internal/models"
+	"rmq_service/internal/unsubscribe"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	apiRequestTimeout = 30 * time.Second
+	maxAPIResponseSize = 1 << 20
+)
+
+// Error reply of the provider HTTP API
+type ProviderError struct {
+	Provider 		string
+	StatusCode 	int
+	Message 		string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s API: %d %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Timeouts, rate limits and server errors may succeed later or by another provider
+func (e *ProviderError) Transient() bool {
+	return e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError
+}
+
+// HTTP client of provider APIs
+func NewAPIClient() *http.Client {
+	return &http.Client{Timeout: apiRequestTimeout}
+}
+
+// Send request to the provider API, returns response with the read body on success
+func doAPIRequest(
+	ctx context.Context,
+	client *http.Client,
+	provider string,
+	req *http.Request,
+	errorMessage func(body []byte) string,
+) (*http.Response, []byte, error) {
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "client.Do")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxAPIResponseSize))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "io.ReadAll")
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		message := errorMessage(body)
+		if message == "" {
+			message = http.StatusText(res.StatusCode)
+		}
+		return nil, nil, &ProviderError{Provider: provider, StatusCode: res.StatusCode, Message: message}
+	}
+
+	return res, body, nil
+}
+
+// Custom and unsubscribe headers of the email
+func apiHeaders(tokens *unsubscribe.Tokens, email *models.Email) (map[string]string, error) {
+	headers := make(map[string]string, len(email.Headers)+2)
+	for name, value := range email.Headers {
+		headers[name] = value
+	}
+
+	unsubscribe, err := unsubscribeHeaders(tokens, email)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range unsubscribe {
+		headers[name] = value
+	}
+	return headers, nil
+}
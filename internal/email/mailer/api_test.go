@@ -0,0 +1,136 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Provider API server of the test replying with the status and body
+func newTestAPIServer(t *testing.T, status int, header http.Header, body string) (*httptest.Server, *http.Request) {
+	t.Helper()
+
+	received := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = *r.Clone(context.Background())
+		io.Copy(io.Discard, r.Body)
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func newTestAPIEmail() *models.Email {
+	return &models.Email{
+		EmailID: 			uuid.New(),
+		From: 				"sender@example.com",
+		To: 					[]string{"to@example.com"},
+		Subject: 			"subject",
+		Body: 				"body",
+		ContentType: 	"text/plain",
+	}
+}
+
+func TestSendGridMailerSend(t *testing.T) {
+	server, received := newTestAPIServer(t, http.StatusAccepted, http.Header{"X-Message-Id": {"sg-message-id"}}, "")
+	m := NewSendGridMailer(server.URL, "sg-key", server.Client(), nil)
+
+	mail := newTestAPIEmail()
+	if err := m.Send(context.Background(), mail); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if mail.ProviderMessageID != "sg-message-id" {
+		t.Fatalf("provider message id %q, want sg-message-id", mail.ProviderMessageID)
+	}
+	if received.URL.Path != sendGridSendPath || received.Header.Get("Authorization") != "Bearer sg-key" {
+		t.Fatalf("request %s with authorization %q", received.URL.Path, received.Header.Get("Authorization"))
+	}
+}
+
+func TestMailgunMailerSend(t *testing.T) {
+	server, received := newTestAPIServer(t, http.StatusOK, nil, `{"id": "<mg-message-id@mg.example.com>", "message": "Queued. Thank you."}`)
+	m := NewMailgunMailer(server.URL, "mg.example.com", "mg-key", server.Client(), nil)
+
+	mail := newTestAPIEmail()
+	if err := m.Send(context.Background(), mail); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if mail.ProviderMessageID != "mg-message-id@mg.example.com" {
+		t.Fatalf("provider message id %q, want mg-message-id@mg.example.com", mail.ProviderMessageID)
+	}
+	if user, key, _ := received.BasicAuth(); received.URL.Path != "/v3/mg.example.com/messages" || user != mailgunAPIUser || key != "mg-key" {
+		t.Fatalf("request %s with user %q", received.URL.Path, user)
+	}
+}
+
+func TestAPIMailersErrors(t *testing.T) {
+	tests := []struct {
+		name 			string
+		status 		int
+		body 			string
+		transient bool
+		message 	string
+	}{
+		{"bad request", http.StatusBadRequest, `{"errors": [{"message": "invalid email", "field": "from"}], "message": "invalid email"}`, false, ""},
+		{"unauthorized", http.StatusUnauthorized, "", false, "Unauthorized"},
+		{"too many requests", http.StatusTooManyRequests, "", true, "Too Many Requests"},
+		{"server error", http.StatusBadGateway, "upstream is down", true, "upstream is down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestAPIServer(t, tt.status, nil, tt.body)
+
+			senders := map[string]email.Mailer{
+				sendGridProvider: NewSendGridMailer(server.URL, "key", server.Client(), nil),
+				mailgunProvider: 	NewMailgunMailer(server.URL, "mg.example.com", "key", server.Client(), nil),
+			}
+
+			for provider, m := range senders {
+				err := m.Send(context.Background(), newTestAPIEmail())
+
+				var providerErr *ProviderError
+				if !errors.As(err, &providerErr) || providerErr.StatusCode != tt.status {
+					t.Fatalf("%s: Send error %v, want %d reply", provider, err, tt.status)
+				}
+				if IsTransientError(err) != tt.transient {
+					t.Fatalf("%s: transient %v, want %v", provider, IsTransientError(err), tt.transient)
+				}
+				if tt.message != "" && providerErr.Message != tt.message {
+					t.Fatalf("%s: message %q, want %q", provider, providerErr.Message, tt.message)
+				}
+			}
+		})
+	}
+}
+
+func TestSendGridErrorMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"errors": [{"message": "invalid email", "field": "from"}, {"message": "no content"}]}`, "from: invalid email; no content"},
+		{"<html>bad gateway</html>\n", "<html>bad gateway</html>"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := sendGridErrorMessage([]byte(tt.body)); got != tt.want {
+			t.Errorf("sendGridErrorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"net/http"
+
+	"rmq_service/config"
+	"rmq_service/internal/bounce"
+	"rmq_service/internal/email"
+	"rmq_service/internal/unsubscribe"
+	"rmq_service/pkg/dkim"
+
+	"github.com/pkg/errors"
+	"gopkg.in/gomail.v2"
+)
+
+// Provider backends
+const (
+	BackendSMTP 		= "smtp"
+	BackendSendGrid = "sendgrid"
+	BackendMailgun 	= "mailgun"
+)
+
+// Mailer of the provider backend, dialer, DKIM signer and return path are used by SMTP providers only
+func NewProviderMailer(
+	provider config.SmtpProvider,
+	dialer *gomail.Dialer,
+	poolCfg config.SmtpPool,
+	client *http.Client,
+	unsubscribeTokens *unsubscribe.Tokens,
+	dkimSigner *dkim.Signer,
+	returnPath *bounce.ReturnPath,
+) (email.Mailer, error) {
+	switch provider.Backend {
+	case "", BackendSMTP:
+		return NewMailer(NewSmtpPool(provider.Name, dialer, poolCfg), unsubscribeTokens, dkimSigner, returnPath), nil
+	case BackendSendGrid:
+		return NewSendGridMailer(provider.BaseURL, provider.APIKey, client, unsubscribeTokens), nil
+	case BackendMailgun:
+		return NewMailgunMailer(provider.BaseURL, provider.Domain, provider.APIKey, client, unsubscribeTokens), nil
+	default:
+		return nil, errors.Errorf("unknown backend %s of provider %s", provider.Backend, provider.Name)
+	}
+}
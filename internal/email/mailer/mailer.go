@@ -1,33 +1,196 @@
 package mailer
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"rmq_service/internal/bounce"
 	"rmq_service/internal/models"
+	"rmq_service/internal/unsubscribe"
+	"rmq_service/pkg/dkim"
+	"rmq_service/pkg/mime_types"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"gopkg.in/gomail.v2"
 )
 
 // Mailer agent
 type Mailer struct {
-	mailDialer *gomail.Dialer
+	pool *SmtpPool
+	unsubscribeTokens *unsubscribe.Tokens
+	dkimSigner *dkim.Signer
+	returnPath *bounce.ReturnPath
 }
 
 // New Mail dialer
-func NewMailer(mailDialer *gomail.Dialer) *Mailer {
-	return &Mailer{ mailDialer: mailDialer }
+func NewMailer(
+	pool *SmtpPool,
+	unsubscribeTokens *unsubscribe.Tokens,
+	dkimSigner *dkim.Signer,
+	returnPath *bounce.ReturnPath,
+) *Mailer {
+	return &Mailer{ pool: pool, unsubscribeTokens: unsubscribeTokens, dkimSigner: dkimSigner, returnPath: returnPath }
 }
 
 // Send email
 func (m *Mailer) Send(ctx context.Context, email *models.Email) error {
-	span, _ := opentracing.StartSpanFromContext(ctx, "Mailer.Send")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Mailer.Send")
 	defer span.Finish()
 
+	// bounce reports quote the Message-ID, so they are matched to the email by it
+	messageID := fmt.Sprintf("%s@%s", email.EmailID, email.GetFromDomain())
+
 	gm := gomail.NewMessage()
+	gm.SetHeader("Message-ID", "<"+messageID+">")
 	gm.SetHeader("From", email.From)
-	gm.SetHeader("To", email.To...)
+	if len(email.To) > 0 {
+		gm.SetHeader("To", email.To...)
+	}
+	if len(email.Cc) > 0 {
+		gm.SetHeader("Cc", email.Cc...)
+	}
+	if len(email.Bcc) > 0 {
+		gm.SetHeader("Bcc", email.Bcc...)
+	}
+	if email.ReplyTo != "" {
+		gm.SetHeader("Reply-To", email.ReplyTo)
+	}
+	for name, value := range email.Headers {
+		gm.SetHeader(name, value)
+	}
+	if err := m.setUnsubscribeHeaders(gm, email); err != nil {
+		return err
+	}
 	gm.SetHeader("Subject", email.Subject)
-	gm.SetBody(email.ContentType, email.Body)
+	// html body goes with the plain text alternative as multipart/alternative,
+	// the preferred part is the last one
+	if email.TextBody != "" {
+		gm.SetBody(mime_types.MIMETextPlain, email.TextBody)
+		gm.AddAlternative(mime_types.MIMETextHTML, email.Body)
+	} else {
+		gm.SetBody(email.ContentType, email.Body)
+	}
+
+	for _, a := range email.Attachments {
+		settings := []gomail.FileSetting{
+			gomail.SetCopyFunc(copyContent(a.Content)),
+			gomail.SetHeader(map[string][]string{
+				"Content-Type": 				{attachmentContentType(a)},
+				"Content-Disposition": 	{attachmentDisposition(a)},
+			}),
+		}
+
+		if a.Inline {
+			gm.Embed(a.Filename, settings...)
+			continue
+		}
+		gm.Attach(a.Filename, settings...)
+	}
+
+	msg, err := m.sign(gm, email)
+	if err != nil {
+		return err
+	}
+
+	if err := m.pool.Send(ctx, m.envelopeFrom(email), email.GetRecipients(), msg); err != nil {
+		return err
+	}
+
+	email.ProviderMessageID = messageID
+	return nil
+}
+
+// Envelope sender of the email, bounces go to the VERP return path when it is set
+func (m *Mailer) envelopeFrom(email *models.Email) string {
+	if !m.returnPath.Enabled() {
+		return email.From
+	}
+	return m.returnPath.Address(email.EmailID)
+}
+
+// Sign the final MIME message with DKIM key of the sender domain
+func (m *Mailer) sign(gm *gomail.Message, email *models.Email) (io.WriterTo, error) {
+	if !m.dkimSigner.Enabled() {
+		return gm, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := gm.WriteTo(buf); err != nil {
+		return nil, errors.Wrap(err, "gm.WriteTo")
+	}
+
+	signed, err := m.dkimSigner.Sign(buf.Bytes(), email.GetFromDomain())
+	if err != nil {
+		return nil, errors.Wrap(err, "dkimSigner.Sign")
+	}
+	return bytes.NewReader(signed), nil
+}
+
+// Close pooled connections
+func (m *Mailer) Close() error {
+	return m.pool.Close()
+}
+
+// Add RFC 8058 one-click unsubscribe headers
+func (m *Mailer) setUnsubscribeHeaders(gm *gomail.Message, email *models.Email) error {
+	headers, err := unsubscribeHeaders(m.unsubscribeTokens, email)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range headers {
+		gm.SetHeader(name, value)
+	}
+	return nil
+}
+
+// RFC 8058 one-click unsubscribe headers.
+// Token is personal, so headers are returned only for categorized emails with a single recipient,
+// bulk emails are always sent one per recipient.
+func unsubscribeHeaders(tokens *unsubscribe.Tokens, email *models.Email) (map[string]string, error) {
+	recipients := email.GetRecipients()
+	if email.Category == "" || len(recipients) != 1 || !tokens.Enabled() {
+		return nil, nil
+	}
+
+	unsubscribeURL, err := tokens.URL(recipients[0], email.Category, email.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"List-Unsubscribe": 			fmt.Sprintf("<%s>", unsubscribeURL),
+		"List-Unsubscribe-Post": 	"List-Unsubscribe=One-Click",
+	}, nil
+}
+
+// Attachment content type with the escaped name parameter, invalid content type falls back to octet stream
+func attachmentContentType(a *models.Attachment) string {
+	mediaType, params, err := mime.ParseMediaType(a.ContentType)
+	if err != nil {
+		mediaType, params = mime_types.MIMEOctetStream, map[string]string{}
+	}
+	params["name"] = filepath.Base(a.Filename)
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// Attachment content disposition with the escaped filename parameter
+func attachmentDisposition(a *models.Attachment) string {
+	disposition := "attachment"
+	if a.Inline {
+		disposition = "inline"
+	}
+	return mime.FormatMediaType(disposition, map[string]string{"filename": filepath.Base(a.Filename)})
+}
 
-	return m.mailDialer.DialAndSend(gm)
+// Write attachment content to the message
+func copyContent(content []byte) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	}
 }
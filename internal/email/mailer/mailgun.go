@@ -0,0 +1,168 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+
+	"rmq_service/internal/models"
+	"rmq_service/internal/unsubscribe"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	mailgunProvider = "Mailgun"
+	mailgunBaseURL 	= "https://api.mailgun.net"
+	mailgunAPIUser 	= "api"
+)
+
+// Mailer sending through the Mailgun messages API
+type MailgunMailer struct {
+	baseURL 	string
+	domain 		string
+	apiKey 		string
+	client 		*http.Client
+	unsubscribeTokens *unsubscribe.Tokens
+}
+
+type mailgunReply struct {
+	ID 			string `json:"id"`
+	Message string `json:"message"`
+}
+
+// Mailgun mailer constructor, default API URL is used when base URL is empty
+func NewMailgunMailer(baseURL, domain, apiKey string, client *http.Client, unsubscribeTokens *unsubscribe.Tokens) *MailgunMailer {
+	if baseURL == "" {
+		baseURL = mailgunBaseURL
+	}
+	return &MailgunMailer{
+		baseURL: 	strings.TrimRight(baseURL, "/"),
+		domain: 	domain,
+		apiKey: 	apiKey,
+		client: 	client,
+		unsubscribeTokens: unsubscribeTokens,
+	}
+}
+
+// Send email, Mailgun message id is set to it
+func (m *MailgunMailer) Send(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "MailgunMailer.Send")
+	defer span.Finish()
+
+	body, contentType, err := m.newMessage(email)
+	if err != nil {
+		return errors.Wrap(err, "MailgunMailer.newMessage")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v3/%s/messages", m.baseURL, m.domain), body)
+	if err != nil {
+		return errors.Wrap(err, "http.NewRequest")
+	}
+	req.SetBasicAuth(mailgunAPIUser, m.apiKey)
+	req.Header.Set("Content-Type", contentType)
+
+	_, replyBody, err := doAPIRequest(ctx, m.client, mailgunProvider, req, mailgunErrorMessage)
+	if err != nil {
+		return err
+	}
+
+	reply := &mailgunReply{}
+	if err := json.Unmarshal(replyBody, reply); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+
+	email.ProviderMessageID = strings.Trim(reply.ID, "<>")
+	return nil
+}
+
+// Build multipart form of the message, returns the form and its content type
+func (m *MailgunMailer) newMessage(email *models.Email) (*bytes.Buffer, string, error) {
+	headers, err := apiHeaders(m.unsubscribeTokens, email)
+	if err != nil {
+		return nil, "", err
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	fields := [][2]string{
+		{"from", email.From},
+		{"subject", email.Subject},
+		// user variables are a part of the delivery events of the email webhook
+		{"v:" + models.DeliveryEventEmailIDVariable, email.EmailID.String()},
+	}
+	for _, to := range email.To {
+		fields = append(fields, [2]string{"to", to})
+	}
+	for _, cc := range email.Cc {
+		fields = append(fields, [2]string{"cc", cc})
+	}
+	for _, bcc := range email.Bcc {
+		fields = append(fields, [2]string{"bcc", bcc})
+	}
+	if email.IsHTML() {
+		fields = append(fields, [2]string{"html", email.Body}, [2]string{"text", email.TextBody})
+	} else {
+		fields = append(fields, [2]string{"text", email.Body})
+	}
+	if email.ReplyTo != "" {
+		fields = append(fields, [2]string{"h:Reply-To", email.ReplyTo})
+	}
+	for name, value := range headers {
+		fields = append(fields, [2]string{"h:" + name, value})
+	}
+	if email.Category != "" {
+		fields = append(fields, [2]string{"o:tag", email.Category})
+	}
+
+	for _, f := range fields {
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return nil, "", errors.Wrap(err, "w.WriteField")
+		}
+	}
+
+	for _, a := range email.Attachments {
+		field := "attachment"
+		if a.Inline {
+			field = "inline"
+		}
+
+		// filename is escaped, CreateFormFile only escapes quotes
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name": 		field,
+			"filename": filepath.Base(a.Filename),
+		}))
+		header.Set("Content-Type", attachmentContentType(a))
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "w.CreatePart")
+		}
+		if _, err := part.Write(a.Content); err != nil {
+			return nil, "", errors.Wrap(err, "part.Write")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", errors.Wrap(err, "w.Close")
+	}
+	return body, w.FormDataContentType(), nil
+}
+
+func mailgunErrorMessage(body []byte) string {
+	reply := &mailgunReply{}
+	if err := json.Unmarshal(body, reply); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+	return reply.Message
+}
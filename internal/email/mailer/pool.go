@@ -0,0 +1,261 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"rmq_service/config"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	defaultPoolSize 						= 4
+	defaultPoolMaxMessages 			= 100
+	defaultPoolIdleTimeout 			= 30 * time.Second
+	defaultPoolHealthCheckAfter = 5 * time.Second
+	defaultPoolWaitTimeout 			= 10 * time.Second
+	defaultPoolDialTimeout 			= 10 * time.Second
+	defaultPoolCommandTimeout 	= time.Minute
+)
+
+var (
+	poolConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "emails_smtp_pool_connections",
+		Help: "The number of open SMTP connections",
+	}, []string{"provider", "state"})
+
+	poolWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: 		"emails_smtp_pool_wait_seconds",
+		Help: 		"Time spent waiting for SMTP connection from the pool",
+		Buckets: 	[]float64{.001, .005, .01, .05, .1, .5, 1, 2.5, 5, 10},
+	}, []string{"provider"})
+
+	errPoolClosed = errors.New("smtp pool is closed")
+)
+
+// Bounded pool of persistent SMTP connections of a single provider
+type SmtpPool struct {
+	name 		string
+	dialer 	*gomail.Dialer
+	cfg 		config.SmtpPool
+	slots 	chan struct{}
+	mu 			sync.Mutex
+	idle 		[]*smtpConn
+	closed 	bool
+	done 		chan struct{}
+}
+
+// SMTP pool constructor, idle connections are closed in background until the pool is closed
+func NewSmtpPool(name string, dialer *gomail.Dialer, cfg config.SmtpPool) *SmtpPool {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultPoolSize
+	}
+	if cfg.MaxMessages <= 0 {
+		cfg.MaxMessages = defaultPoolMaxMessages
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultPoolIdleTimeout
+	}
+	if cfg.HealthCheckAfter <= 0 {
+		cfg.HealthCheckAfter = defaultPoolHealthCheckAfter
+	}
+	if cfg.WaitTimeout <= 0 {
+		cfg.WaitTimeout = defaultPoolWaitTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultPoolDialTimeout
+	}
+	if cfg.CommandTimeout <= 0 {
+		cfg.CommandTimeout = defaultPoolCommandTimeout
+	}
+
+	p := &SmtpPool{
+		name: 	name,
+		dialer: dialer,
+		cfg: 		cfg,
+		slots: 	make(chan struct{}, cfg.Size),
+		done: 	make(chan struct{}),
+	}
+	go p.closeIdle()
+	return p
+}
+
+// Send message over pooled connection, connection failed to send is closed
+// and the next message is sent over a new one
+func (p *SmtpPool) Send(ctx context.Context, from string, to []string, msg io.WriterTo) error {
+	conn, err := p.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.Send(from, to, msg)
+	p.put(conn, err)
+	return err
+}
+
+// Close idle connections and stop the pool, busy connections are closed when returned
+func (p *SmtpPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	close(p.done)
+	for _, conn := range idle {
+		p.closeConn(conn, "idle")
+	}
+	return nil
+}
+
+// Take connection slot and reuse healthy idle connection or dial a new one
+func (p *SmtpPool) get(ctx context.Context) (*smtpConn, error) {
+	if err := p.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		conn, err := p.popIdle()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		if conn == nil {
+			break
+		}
+
+		if time.Since(conn.lastUsed) < p.cfg.HealthCheckAfter {
+			return conn, nil
+		}
+		if err := conn.Noop(); err == nil {
+			return conn, nil
+		}
+		p.closeConn(conn, "busy")
+	}
+
+	conn, err := p.dial(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, errors.Wrap(err, "SmtpPool.dial")
+	}
+	return conn, nil
+}
+
+// Wait for free connection slot
+func (p *SmtpPool) acquire(ctx context.Context) error {
+	start := time.Now()
+	defer func() {
+		poolWaitSeconds.WithLabelValues(p.name).Observe(time.Since(start).Seconds())
+	}()
+
+	timer := time.NewTimer(p.cfg.WaitTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return errors.Errorf("SmtpPool.acquire: no free connection of %s in %v", p.name, p.cfg.WaitTimeout)
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "SmtpPool.acquire")
+	}
+}
+
+// Most recently used idle connection, so the rest can reach the idle timeout
+func (p *SmtpPool) popIdle() (*smtpConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, errPoolClosed
+	}
+
+	if len(p.idle) == 0 {
+		return nil, nil
+	}
+
+	conn := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	poolConnections.WithLabelValues(p.name, "idle").Dec()
+	poolConnections.WithLabelValues(p.name, "busy").Inc()
+	return conn, nil
+}
+
+// Return connection to the pool, failed and worn out connections are closed
+func (p *SmtpPool) put(conn *smtpConn, sendErr error) {
+	defer func() { <-p.slots }()
+
+	if sendErr != nil || conn.sent >= p.cfg.MaxMessages {
+		p.closeConn(conn, "busy")
+		return
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.closeConn(conn, "busy")
+		return
+	}
+	conn.lastUsed = time.Now()
+	p.idle = append(p.idle, conn)
+	poolConnections.WithLabelValues(p.name, "busy").Dec()
+	poolConnections.WithLabelValues(p.name, "idle").Inc()
+	p.mu.Unlock()
+}
+
+// Dial new busy connection
+func (p *SmtpPool) dial(ctx context.Context) (*smtpConn, error) {
+	conn, err := dialSMTP(ctx, p.dialer, p.cfg.DialTimeout, p.cfg.CommandTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConnections.WithLabelValues(p.name, "busy").Inc()
+	return conn, nil
+}
+
+func (p *SmtpPool) closeConn(conn *smtpConn, state string) {
+	poolConnections.WithLabelValues(p.name, state).Dec()
+	conn.Close()
+}
+
+// Close connections idle longer than the idle timeout
+func (p *SmtpPool) closeIdle() {
+	ticker := time.NewTicker(p.cfg.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		expired := make([]*smtpConn, 0)
+		kept := p.idle[:0]
+		for _, conn := range p.idle {
+			if time.Since(conn.lastUsed) >= p.cfg.IdleTimeout {
+				expired = append(expired, conn)
+				continue
+			}
+			kept = append(kept, conn)
+		}
+		p.idle = kept
+		p.mu.Unlock()
+
+		for _, conn := range expired {
+			p.closeConn(conn, "idle")
+		}
+	}
+}
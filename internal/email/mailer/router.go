@@ -0,0 +1,264 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/textproto"
+	"sort"
+	"sync"
+	"time"
+
+	"rmq_service/internal/email"
+	"rmq_service/internal/email/ratelimit"
+	"rmq_service/internal/models"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	providerSentEmails = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_provider_sent_total",
+		Help: "The total number of emails sent by SMTP provider",
+	}, []string{"provider"})
+
+	providerFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_provider_failovers_total",
+		Help: "The total number of failovers from SMTP provider",
+	}, []string{"provider"})
+
+	providerThrottles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_provider_throttles_total",
+		Help: "The total number of emails skipped by rate limited SMTP provider",
+	}, []string{"provider"})
+)
+
+// SMTP provider of the routing mailer, send rate of the provider is limited by its account.
+// Emails sent by the provider with sender are sent from it.
+type Provider struct {
+	Name 			string
+	Account 	string
+	From 			string
+	Weight 		int
+	Priority 	int
+	Mailer 		email.Mailer
+}
+
+// Mailer routing emails across SMTP providers
+type RoutingMailer struct {
+	groups 	[][]*Provider
+	limiter email.RateLimiter
+	mu 			sync.Mutex
+	rnd 		*rand.Rand
+}
+
+// Routing mailer constructor, providers are grouped by priority. Send rate is not limited without limiter.
+func NewRoutingMailer(providers []*Provider, limiter email.RateLimiter) *RoutingMailer {
+	sorted := make([]*Provider, len(providers))
+	copy(sorted, providers)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+
+	groups := make([][]*Provider, 0, len(sorted))
+	for i, p := range sorted {
+		if i == 0 || p.Priority != sorted[i-1].Priority {
+			groups = append(groups, []*Provider{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], p)
+	}
+
+	return &RoutingMailer{groups: groups, limiter: limiter, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Send email by the first available provider, fails over to the next provider
+// on connection and transient SMTP errors and when the provider account is rate limited.
+// Global and recipients domains tokens are taken once before any provider is tried.
+// Provider which sent the email is set to it. When no provider sent the email
+// and some were rate limited, LimitExceededError with the shortest delay is returned.
+// Error which is not transient wraps email.ErrPermanentFailure.
+func (m *RoutingMailer) Send(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RoutingMailer.Send")
+	defer span.Finish()
+
+	// sender is restored when no provider sent the email
+	from := email.From
+	sent := false
+	defer func() {
+		if !sent {
+			email.From = from
+		}
+	}()
+
+	delay, err := m.takeSendTokens(ctx, email)
+	if err != nil {
+		return err
+	}
+	if delay > 0 {
+		return &ratelimit.LimitExceededError{Delay: delay}
+	}
+
+	var lastErr error
+	var limitDelay time.Duration
+	for _, p := range m.route() {
+		delay, err := m.takeAccountTokens(ctx, p)
+		if err != nil {
+			return errors.Wrapf(err, "provider %s", p.Name)
+		}
+		if delay > 0 {
+			if limitDelay == 0 || delay < limitDelay {
+				limitDelay = delay
+			}
+			providerThrottles.WithLabelValues(p.Name).Inc()
+			continue
+		}
+
+		email.From = from
+		if p.From != "" {
+			email.From = p.From
+		}
+
+		err = p.Mailer.Send(ctx, email)
+		if err == nil {
+			sent = true
+			email.Provider = p.Name
+			providerSentEmails.WithLabelValues(p.Name).Inc()
+			span.LogFields(log.String("provider", p.Name))
+			return nil
+		}
+
+		lastErr = errors.Wrapf(err, "provider %s", p.Name)
+		if !IsTransientError(err) {
+			return permanentError(lastErr)
+		}
+		providerFailovers.WithLabelValues(p.Name).Inc()
+	}
+
+	// transient failures of other providers are retried after the delay as well
+	if limitDelay > 0 {
+		return &ratelimit.LimitExceededError{Delay: limitDelay}
+	}
+	if lastErr == nil {
+		return errors.New("RoutingMailer.Send: no SMTP providers")
+	}
+	return lastErr
+}
+
+// Take global and recipients domains send tokens, returns delay when any of them is rate limited
+func (m *RoutingMailer) takeSendTokens(ctx context.Context, email *models.Email) (time.Duration, error) {
+	if m.limiter == nil {
+		return 0, nil
+	}
+
+	delay, err := m.limiter.Take(ctx, email.GetRecipients())
+	if err != nil {
+		return 0, errors.Wrap(err, "limiter.Take")
+	}
+	return delay, nil
+}
+
+// Take send token of the provider account, returns delay when the account is rate limited
+func (m *RoutingMailer) takeAccountTokens(ctx context.Context, p *Provider) (time.Duration, error) {
+	if m.limiter == nil {
+		return 0, nil
+	}
+
+	account := p.Account
+	if account == "" {
+		account = p.Name
+	}
+
+	delay, err := m.limiter.TakeAccount(ctx, account)
+	if err != nil {
+		return 0, errors.Wrap(err, "limiter.TakeAccount")
+	}
+	return delay, nil
+}
+
+// Close mailers of the providers
+func (m *RoutingMailer) Close() error {
+	var closeErr error
+	for _, group := range m.groups {
+		for _, p := range group {
+			if closer, ok := p.Mailer.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					closeErr = errors.Wrapf(err, "provider %s", p.Name)
+				}
+			}
+		}
+	}
+	return closeErr
+}
+
+// Providers in the order they are tried, weighted random order within each priority group
+func (m *RoutingMailer) route() []*Provider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	route := make([]*Provider, 0)
+	for _, group := range m.groups {
+		route = append(route, m.shuffle(group)...)
+	}
+	return route
+}
+
+// Weighted random order of the providers, providers without weight get the minimal one
+func (m *RoutingMailer) shuffle(group []*Provider) []*Provider {
+	left := make([]*Provider, len(group))
+	copy(left, group)
+
+	shuffled := make([]*Provider, 0, len(group))
+	for len(left) > 0 {
+		total := 0
+		for _, p := range left {
+			total += providerWeight(p)
+		}
+
+		n := m.rnd.Intn(total)
+		for i, p := range left {
+			if n -= providerWeight(p); n < 0 {
+				shuffled = append(shuffled, p)
+				left = append(left[:i], left[i+1:]...)
+				break
+			}
+		}
+	}
+	return shuffled
+}
+
+func providerWeight(p *Provider) int {
+	if p.Weight <= 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+// Mark the error to be not retried
+func permanentError(err error) error {
+	return fmt.Errorf("%w: %w", email.ErrPermanentFailure, err)
+}
+
+// Check if sending may succeed by another provider: connection errors, 4xx SMTP replies
+// and transient API errors
+func IsTransientError(err error) bool {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Transient()
+	}
+
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code >= 400 && smtpErr.Code < 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,293 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"rmq_service/internal/email"
+	"rmq_service/internal/email/ratelimit"
+	"rmq_service/internal/models"
+)
+
+// Mailer of the test provider recording sends
+type fakeMailer struct {
+	name 	string
+	err 	error
+	sent 	*[]string
+	from 	*[]string
+}
+
+func (m *fakeMailer) Send(_ context.Context, email *models.Email) error {
+	*m.sent = append(*m.sent, m.name)
+	*m.from = append(*m.from, email.From)
+	return m.err
+}
+
+// Rate limiter of the test, limited accounts get their delay, taken tokens are counted
+type fakeLimiter struct {
+	delay 				time.Duration
+	accounts 			map[string]time.Duration
+	taken 				int
+	accountsTaken []string
+}
+
+func (l *fakeLimiter) Take(_ context.Context, _ []string) (time.Duration, error) {
+	if l.delay == 0 {
+		l.taken++
+	}
+	return l.delay, nil
+}
+
+func (l *fakeLimiter) TakeAccount(_ context.Context, account string) (time.Duration, error) {
+	l.accountsTaken = append(l.accountsTaken, account)
+	return l.accounts[account], nil
+}
+
+type testProvider struct {
+	name 			string
+	weight 		int
+	priority 	int
+	from 			string
+	err 			error
+}
+
+func newTestRouter(providers []testProvider, limiter email.RateLimiter) (*RoutingMailer, *[]string, *[]string) {
+	sent := &[]string{}
+	from := &[]string{}
+	routed := make([]*Provider, 0, len(providers))
+	for _, p := range providers {
+		routed = append(routed, &Provider{
+			Name: 		p.name,
+			From: 		p.from,
+			Weight: 	p.weight,
+			Priority: p.priority,
+			Mailer: 	&fakeMailer{name: p.name, err: p.err, sent: sent, from: from},
+		})
+	}
+
+	router := NewRoutingMailer(routed, limiter)
+	router.rnd = rand.New(rand.NewSource(1))
+	return router, sent, from
+}
+
+func TestRoutingMailerPriorityGroups(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "backup", priority: 1},
+		{name: "primary", priority: 0},
+	}, nil)
+
+	email := &models.Email{From: "sender@example.com"}
+	if err := router.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if email.Provider != "primary" {
+		t.Fatalf("provider %s, want primary", email.Provider)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("sent by %v, want primary only", *sent)
+	}
+}
+
+func TestRoutingMailerWeights(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "heavy", weight: 3},
+		{name: "light", weight: 1},
+		{name: "backup", weight: 100, priority: 1},
+	}, nil)
+
+	const sends = 4000
+	for i := 0; i < sends; i++ {
+		if err := router.Send(context.Background(), &models.Email{}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, name := range *sent {
+		counts[name]++
+	}
+
+	if counts["backup"] != 0 {
+		t.Fatalf("lower priority provider sent %d emails", counts["backup"])
+	}
+	if share := float64(counts["heavy"]) / sends; share < 0.7 || share > 0.8 {
+		t.Fatalf("heavy provider share %.2f, want about 0.75", share)
+	}
+}
+
+func TestRoutingMailerFailover(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary", err: &textproto.Error{Code: 421, Msg: "try again later"}},
+		{name: "backup", priority: 1},
+	}, nil)
+
+	email := &models.Email{}
+	if err := router.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if email.Provider != "backup" {
+		t.Fatalf("provider %s, want backup", email.Provider)
+	}
+	if len(*sent) != 2 {
+		t.Fatalf("sent by %v, want primary then backup", *sent)
+	}
+}
+
+func TestRoutingMailerNoFailoverOnPermanentError(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary", err: &textproto.Error{Code: 550, Msg: "mailbox unavailable"}},
+		{name: "backup", priority: 1},
+	}, nil)
+
+	mail := &models.Email{}
+	err := router.Send(context.Background(), mail)
+
+	var smtpErr *textproto.Error
+	if !errors.As(err, &smtpErr) || smtpErr.Code != 550 {
+		t.Fatalf("Send error %v, want 550 reply", err)
+	}
+	if !errors.Is(err, email.ErrPermanentFailure) {
+		t.Fatalf("Send error %v, want permanent failure", err)
+	}
+	if len(*sent) != 1 || mail.Provider != "" {
+		t.Fatalf("sent by %v, provider %q, want no failover", *sent, mail.Provider)
+	}
+}
+
+func TestRoutingMailerAllProvidersFailed(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary", err: &textproto.Error{Code: 421, Msg: "try again later"}},
+		{name: "backup", priority: 1, err: &textproto.Error{Code: 451, Msg: "local error"}},
+	}, nil)
+
+	err := router.Send(context.Background(), &models.Email{})
+
+	var smtpErr *textproto.Error
+	if !errors.As(err, &smtpErr) || smtpErr.Code != 451 {
+		t.Fatalf("Send error %v, want last provider error", err)
+	}
+	if errors.Is(err, email.ErrPermanentFailure) {
+		t.Fatalf("Send error %v, want transient failure", err)
+	}
+	if len(*sent) != 2 {
+		t.Fatalf("sent by %v, want both providers", *sent)
+	}
+}
+
+func TestRoutingMailerNoProviders(t *testing.T) {
+	router, _, _ := newTestRouter(nil, nil)
+
+	if err := router.Send(context.Background(), &models.Email{}); err == nil {
+		t.Fatal("Send without providers succeeded")
+	}
+}
+
+func TestRoutingMailerRateLimitedProvider(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary"},
+		{name: "backup", priority: 1},
+	}, &fakeLimiter{accounts: map[string]time.Duration{"primary": time.Second}})
+
+	email := &models.Email{}
+	if err := router.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if email.Provider != "backup" || len(*sent) != 1 {
+		t.Fatalf("sent by %v, provider %s, want backup only", *sent, email.Provider)
+	}
+}
+
+func TestRoutingMailerAllProvidersRateLimited(t *testing.T) {
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary"},
+		{name: "backup", priority: 1},
+	}, &fakeLimiter{accounts: map[string]time.Duration{"primary": 3 * time.Second, "backup": time.Second}})
+
+	err := router.Send(context.Background(), &models.Email{})
+
+	var limitErr *ratelimit.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Delay != time.Second {
+		t.Fatalf("Send error %v, want limit exceeded with the shortest delay", err)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("sent by %v, want none", *sent)
+	}
+}
+
+func TestRoutingMailerSendTokensTakenOnce(t *testing.T) {
+	limiter := &fakeLimiter{}
+	router, sent, _ := newTestRouter([]testProvider{
+		{name: "primary", err: &textproto.Error{Code: 421, Msg: "try again later"}},
+		{name: "backup", priority: 1},
+	}, limiter)
+
+	if err := router.Send(context.Background(), &models.Email{To: []string{"to@example.com"}}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if len(*sent) != 2 {
+		t.Fatalf("sent by %v, want primary then backup", *sent)
+	}
+	if limiter.taken != 1 {
+		t.Fatalf("send tokens taken %d times, want once per email", limiter.taken)
+	}
+	if len(limiter.accountsTaken) != 2 {
+		t.Fatalf("account tokens taken by %v, want both providers", limiter.accountsTaken)
+	}
+}
+
+func TestRoutingMailerRecipientsRateLimited(t *testing.T) {
+	limiter := &fakeLimiter{delay: 2 * time.Second}
+	router, sent, _ := newTestRouter([]testProvider{{name: "primary"}}, limiter)
+
+	err := router.Send(context.Background(), &models.Email{To: []string{"to@example.com"}})
+
+	var limitErr *ratelimit.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Delay != 2*time.Second {
+		t.Fatalf("Send error %v, want limit exceeded", err)
+	}
+	if len(*sent) != 0 || len(limiter.accountsTaken) != 0 {
+		t.Fatalf("sent by %v, account tokens taken by %v, want none", *sent, limiter.accountsTaken)
+	}
+}
+
+func TestRoutingMailerProviderSender(t *testing.T) {
+	router, _, from := newTestRouter([]testProvider{
+		{name: "primary", from: "noreply@mg.example.com", err: &textproto.Error{Code: 421, Msg: "try again later"}},
+		{name: "backup", priority: 1},
+	}, nil)
+
+	email := &models.Email{From: "sender@example.com"}
+	if err := router.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	want := []string{"noreply@mg.example.com", "sender@example.com"}
+	if len(*from) != len(want) || (*from)[0] != want[0] || (*from)[1] != want[1] {
+		t.Fatalf("senders %v, want %v", *from, want)
+	}
+	if email.From != "sender@example.com" {
+		t.Fatalf("email sender %s, want the backup one", email.From)
+	}
+}
+
+func TestRoutingMailerSenderRestoredOnFailure(t *testing.T) {
+	router, _, _ := newTestRouter([]testProvider{
+		{name: "primary", from: "noreply@mg.example.com", err: &textproto.Error{Code: 550, Msg: "rejected"}},
+	}, nil)
+
+	email := &models.Email{From: "sender@example.com"}
+	if err := router.Send(context.Background(), email); err == nil {
+		t.Fatal("Send succeeded")
+	}
+	if email.From != "sender@example.com" {
+		t.Fatalf("email sender %s, want restored sender", email.From)
+	}
+}
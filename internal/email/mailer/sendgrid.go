@@ -0,0 +1,202 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"rmq_service/internal/models"
+	"rmq_service/internal/unsubscribe"
+	"rmq_service/pkg/mime_types"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	sendGridProvider 		= "SendGrid"
+	sendGridBaseURL 		= "https://api.sendgrid.com"
+	sendGridSendPath 		= "/v3/mail/send"
+	sendGridMessageIDHeader = "X-Message-Id"
+)
+
+// Mailer sending through the SendGrid v3 mail send API
+type SendGridMailer struct {
+	baseURL 	string
+	apiKey 		string
+	client 		*http.Client
+	unsubscribeTokens *unsubscribe.Tokens
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridPersonalization struct {
+	To 	[]sendGridAddress `json:"to,omitempty"`
+	Cc 	[]sendGridAddress `json:"cc,omitempty"`
+	Bcc []sendGridAddress `json:"bcc,omitempty"`
+}
+
+type sendGridContent struct {
+	Type 	string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content 		string `json:"content"`
+	Type 				string `json:"type,omitempty"`
+	Filename 		string `json:"filename"`
+	Disposition string `json:"disposition"`
+	ContentID 	string `json:"content_id,omitempty"`
+}
+
+type sendGridMessage struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From 				sendGridAddress 			`json:"from"`
+	ReplyTo 		*sendGridAddress 			`json:"reply_to,omitempty"`
+	Subject 		string 								`json:"subject"`
+	Content 		[]sendGridContent 		`json:"content"`
+	Attachments []sendGridAttachment 	`json:"attachments,omitempty"`
+	Headers 		map[string]string 		`json:"headers,omitempty"`
+	Categories 	[]string 							`json:"categories,omitempty"`
+	CustomArgs 	map[string]string 		`json:"custom_args,omitempty"`
+}
+
+type sendGridErrors struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Field 	string `json:"field"`
+	} `json:"errors"`
+}
+
+// SendGrid mailer constructor, default API URL is used when base URL is empty
+func NewSendGridMailer(baseURL, apiKey string, client *http.Client, unsubscribeTokens *unsubscribe.Tokens) *SendGridMailer {
+	if baseURL == "" {
+		baseURL = sendGridBaseURL
+	}
+	return &SendGridMailer{
+		baseURL: 	strings.TrimRight(baseURL, "/"),
+		apiKey: 	apiKey,
+		client: 	client,
+		unsubscribeTokens: unsubscribeTokens,
+	}
+}
+
+// Send email, SendGrid message id is set to it
+func (m *SendGridMailer) Send(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SendGridMailer.Send")
+	defer span.Finish()
+
+	message, err := m.newMessage(email)
+	if err != nil {
+		return errors.Wrap(err, "SendGridMailer.newMessage")
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.baseURL+sendGridSendPath, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "http.NewRequest")
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", mime_types.MIMEApplicationJSON)
+
+	res, _, err := doAPIRequest(ctx, m.client, sendGridProvider, req, sendGridErrorMessage)
+	if err != nil {
+		return err
+	}
+
+	email.ProviderMessageID = res.Header.Get(sendGridMessageIDHeader)
+	return nil
+}
+
+// Build mail send payload, plain text content goes first as SendGrid requires
+func (m *SendGridMailer) newMessage(email *models.Email) (*sendGridMessage, error) {
+	headers, err := apiHeaders(m.unsubscribeTokens, email)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &sendGridMessage{
+		Personalizations: []sendGridPersonalization{{
+			To: 	sendGridAddresses(email.To),
+			Cc: 	sendGridAddresses(email.Cc),
+			Bcc: 	sendGridAddresses(email.Bcc),
+		}},
+		From: 		sendGridAddress{Email: email.From},
+		Subject: 	email.Subject,
+		Headers: 	headers,
+		// custom args are a part of the delivery events of the email webhook
+		CustomArgs: map[string]string{models.DeliveryEventEmailIDVariable: email.EmailID.String()},
+	}
+
+	if email.ReplyTo != "" {
+		message.ReplyTo = &sendGridAddress{Email: email.ReplyTo}
+	}
+
+	if email.Category != "" {
+		message.Categories = []string{email.Category}
+	}
+
+	if email.TextBody != "" {
+		message.Content = []sendGridContent{
+			{Type: mime_types.MIMETextPlain, Value: email.TextBody},
+			{Type: mime_types.MIMETextHTML, Value: email.Body},
+		}
+	} else {
+		message.Content = []sendGridContent{{Type: email.ContentType, Value: email.Body}}
+	}
+
+	for _, a := range email.Attachments {
+		attachment := sendGridAttachment{
+			Content: 			base64.StdEncoding.EncodeToString(a.Content),
+			Type: 				a.ContentType,
+			Filename: 		filepath.Base(a.Filename),
+			Disposition: 	"attachment",
+		}
+		if a.Inline {
+			attachment.Disposition = "inline"
+			attachment.ContentID = filepath.Base(a.Filename)
+		}
+		message.Attachments = append(message.Attachments, attachment)
+	}
+
+	return message, nil
+}
+
+func sendGridAddresses(addresses []string) []sendGridAddress {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	result := make([]sendGridAddress, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, sendGridAddress{Email: address})
+	}
+	return result
+}
+
+func sendGridErrorMessage(body []byte) string {
+	reply := &sendGridErrors{}
+	if err := json.Unmarshal(body, reply); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	messages := make([]string, 0, len(reply.Errors))
+	for _, e := range reply.Errors {
+		if e.Field != "" {
+			messages = append(messages, e.Field+": "+e.Message)
+			continue
+		}
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,199 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// SMTP connection implementing gomail.SendCloser, unlike the gomail one it can be health checked.
+// Every command gets the deadline, so a stalled server never blocks the sender.
+type smtpConn struct {
+	client 		*smtp.Client
+	conn 			net.Conn
+	timeout 	time.Duration
+	sent 			int
+	lastUsed 	time.Time
+}
+
+// Dial and authenticate to the SMTP server the same way gomail.Dialer does,
+// the whole handshake has to finish within the command timeout
+func dialSMTP(ctx context.Context, d *gomail.Dialer, dialTimeout, commandTimeout time.Duration) (*smtpConn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", d.Host, d.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(commandTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	rawConn := conn
+
+	tlsConfig := d.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: d.Host}
+	}
+
+	if d.SSL {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, d.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if d.LocalName != "" {
+		if err := c.Hello(d.LocalName); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	if !d.SSL {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if auth := dialerAuth(d, c); auth != nil {
+		if err := c.Auth(auth); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	return &smtpConn{client: c, conn: rawConn, timeout: commandTimeout, lastUsed: time.Now()}, nil
+}
+
+// Auth mechanism of the dialer, picked from the server supported ones when not set
+func dialerAuth(d *gomail.Dialer, c *smtp.Client) smtp.Auth {
+	if d.Auth != nil || d.Username == "" {
+		return d.Auth
+	}
+
+	ok, auths := c.Extension("AUTH")
+	if !ok {
+		return nil
+	}
+
+	if strings.Contains(auths, "CRAM-MD5") {
+		return smtp.CRAMMD5Auth(d.Username, d.Password)
+	}
+	if strings.Contains(auths, "LOGIN") && !strings.Contains(auths, "PLAIN") {
+		return &loginAuth{username: d.Username, password: d.Password}
+	}
+	return smtp.PlainAuth("", d.Username, d.Password, d.Host)
+}
+
+// Set deadline of the next command
+func (c *smtpConn) deadline() error {
+	return c.conn.SetDeadline(time.Now().Add(c.timeout))
+}
+
+// Send message in a single SMTP transaction
+func (c *smtpConn) Send(from string, to []string, msg io.WriterTo) error {
+	if err := c.deadline(); err != nil {
+		return err
+	}
+	if err := c.client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, addr := range to {
+		if err := c.deadline(); err != nil {
+			return err
+		}
+		if err := c.client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	if err := c.deadline(); err != nil {
+		return err
+	}
+	w, err := c.client.Data()
+	if err != nil {
+		return err
+	}
+
+	// message content and the final reply share the deadline of a single command
+	if err := c.deadline(); err != nil {
+		w.Close()
+		return err
+	}
+	if _, err := msg.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	c.sent++
+	return nil
+}
+
+// Check the connection is still alive
+func (c *smtpConn) Noop() error {
+	if err := c.deadline(); err != nil {
+		return err
+	}
+	return c.client.Noop()
+}
+
+// Quit the session, the connection is closed even if the server does not reply
+func (c *smtpConn) Close() error {
+	if err := c.deadline(); err != nil {
+		c.client.Close()
+		return err
+	}
+	if err := c.client.Quit(); err != nil {
+		c.client.Close()
+		return err
+	}
+	return nil
+}
+
+// LOGIN auth mechanism, not provided by net/smtp
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(string(fromServer)) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
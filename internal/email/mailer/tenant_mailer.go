@@ -0,0 +1,167 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"rmq_service/config"
+	"rmq_service/internal/bounce"
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/internal/unsubscribe"
+	"rmq_service/pkg/dkim"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gopkg.in/gomail.v2"
+)
+
+// Routing mailer of the tenant providers built from the tenant version
+type tenantRoutingMailer struct {
+	mailer 		*RoutingMailer
+	updatedAt time.Time
+}
+
+// Mailer sending emails of the tenant through its own providers,
+// emails of the default tenant and tenants without providers go through the default mailer
+type TenantMailer struct {
+	defaultMailer 		email.Mailer
+	poolCfg 					config.SmtpPool
+	client 						*http.Client
+	unsubscribeTokens *unsubscribe.Tokens
+	dkimSigner 				*dkim.Signer
+	returnPath 				*bounce.ReturnPath
+	limiter 					email.RateLimiter
+	mu 								sync.Mutex
+	mailers 					map[uuid.UUID]*tenantRoutingMailer
+}
+
+// Tenant mailer constructor
+func NewTenantMailer(
+	defaultMailer email.Mailer,
+	poolCfg config.SmtpPool,
+	client *http.Client,
+	unsubscribeTokens *unsubscribe.Tokens,
+	dkimSigner *dkim.Signer,
+	returnPath *bounce.ReturnPath,
+	limiter email.RateLimiter,
+) *TenantMailer {
+	return &TenantMailer{
+		defaultMailer: 			defaultMailer,
+		poolCfg: 						poolCfg,
+		client: 						client,
+		unsubscribeTokens: 	unsubscribeTokens,
+		dkimSigner: 				dkimSigner,
+		returnPath: 				returnPath,
+		limiter: 						limiter,
+		mailers: 						map[uuid.UUID]*tenantRoutingMailer{},
+	}
+}
+
+// Send email by the mailer of the context tenant
+func (m *TenantMailer) Send(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantMailer.Send")
+	defer span.Finish()
+
+	t, ok := tenant.FromContext(ctx)
+	if !ok || len(t.Providers) == 0 {
+		return m.defaultMailer.Send(ctx, email)
+	}
+
+	mailer, err := m.tenantMailer(t)
+	if err != nil {
+		return errors.Wrap(err, "TenantMailer.tenantMailer")
+	}
+	return mailer.Send(ctx, email)
+}
+
+// Close the default mailer and mailers of the tenants
+func (m *TenantMailer) Close() error {
+	var closeErr error
+	if closer, ok := m.defaultMailer.(io.Closer); ok {
+		closeErr = closer.Close()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, cached := range m.mailers {
+		if err := cached.mailer.Close(); err != nil {
+			closeErr = errors.Wrapf(err, "tenant %v", id)
+		}
+		delete(m.mailers, id)
+	}
+	return closeErr
+}
+
+// Cached mailer of the tenant, mailer is rebuilt when the tenant is updated.
+// Sends in flight on the replaced mailer finish before its connections are closed.
+func (m *TenantMailer) tenantMailer(t *models.Tenant) (*RoutingMailer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, ok := m.mailers[t.TenantID]
+	if ok && cached.updatedAt.Equal(t.UpdatedAt) {
+		return cached.mailer, nil
+	}
+
+	mailer, err := m.newTenantMailer(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		if err := cached.mailer.Close(); err != nil {
+			return nil, errors.Wrap(err, "RoutingMailer.Close")
+		}
+	}
+	m.mailers[t.TenantID] = &tenantRoutingMailer{mailer: mailer, updatedAt: t.UpdatedAt}
+	return mailer, nil
+}
+
+// Routing mailer across the tenant providers, pools are named by tenant to keep their metrics apart
+func (m *TenantMailer) newTenantMailer(t *models.Tenant) (*RoutingMailer, error) {
+	providers := make([]*Provider, 0, len(t.Providers))
+	for _, tp := range t.Providers {
+		p := config.SmtpProvider{
+			Name: 		tp.Name,
+			Backend: 	tp.Backend,
+			Host: 		tp.Host,
+			Port: 		tp.Port,
+			User: 		tp.User,
+			Password: tp.Password,
+			APIKey: 	tp.APIKey,
+			BaseURL: 	tp.BaseURL,
+			Domain: 	tp.Domain,
+			From: 		tp.From,
+			Weight: 	tp.Weight,
+			Priority: tp.Priority,
+		}
+
+		var dialer *gomail.Dialer
+		if p.IsSMTP() {
+			dialer = gomail.NewDialer(p.Host, p.Port, p.User, p.Password)
+		}
+
+		poolProvider := p
+		poolProvider.Name = t.TenantID.String() + "/" + p.Name
+		providerMailer, err := NewProviderMailer(poolProvider, dialer, m.poolCfg, m.client, m.unsubscribeTokens, m.dkimSigner, m.returnPath)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, &Provider{
+			Name: 		p.Name,
+			Account: 	poolProvider.Name,
+			From: 		p.From,
+			Weight: 	p.Weight,
+			Priority: p.Priority,
+			Mailer: 	providerMailer,
+		})
+	}
+	return NewRoutingMailer(providers, m.limiter), nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"rmq_service/internal/models"
 	"rmq_service/pkg/utils"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,6 +14,23 @@ import (
 // Repository interface
 type EmailsRepository interface {
 	CreateEmail(context.Context, *models.Email) (*models.Email, error)
-	FindEmailById(context.Context, uuid.UUID) (*models.Email, error)
-	FindEmailsByReceiver(context.Context, string, *utils.PaginationQuery) (*models.EmailsList, error)
+	UpdateEmailStatus(ctx context.Context, id uuid.UUID, status, reason string, webhookDelivery *models.WebhookDelivery) error
+	ResumeEmailSending(ctx context.Context, id uuid.UUID) (bool, error)
+	UpdateEmailProvider(ctx context.Context, id uuid.UUID, provider, providerMessageID string) error
+	FindEmailStatusTransitions(context.Context, uuid.UUID) ([]*models.EmailStatusTransition, error)
+	CreateEmailEvent(context.Context, *models.EmailEvent) (bool, error)
+	FindEmailEvents(context.Context, uuid.UUID) ([]*models.EmailEvent, error)
+	IsIdempotencyKeyProcessed(ctx context.Context, tenantID *uuid.UUID, key string) (bool, error)
+	MarkIdempotencyKeyProcessed(ctx context.Context, tenantID *uuid.UUID, key string) error
+	CreateScheduledEmail(context.Context, *models.ScheduledEmail) error
+	ClaimDueScheduledEmails(ctx context.Context, limit int, lease time.Duration) ([]*models.ScheduledEmail, error)
+	CompleteScheduledEmail(context.Context, uuid.UUID) error
+	RescheduleEmail(context.Context, *models.ScheduledEmail) error
+	CreateEmailBatch(ctx context.Context, tenantID *uuid.UUID, total int) (*models.EmailBatch, error)
+	FindEmailBatchProgress(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*models.EmailBatchProgress, error)
+	FindEmailRecipients(context.Context, uuid.UUID) ([]string, error)
+	FindEmailIdByMessageID(ctx context.Context, messageID string) (uuid.UUID, error)
+	FindEmailById(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*models.Email, error)
+	FindEmailByIdOfAnyTenant(ctx context.Context, id uuid.UUID) (*models.Email, error)
+	FindEmailsByReceiver(ctx context.Context, tenantID *uuid.UUID, to string, query *utils.PaginationQuery) (*models.EmailsList, error)
 }
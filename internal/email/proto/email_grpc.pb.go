@@ -25,6 +25,30 @@ type EmailServiceClient interface {
 	SendEmails(ctx context.Context, in *SendEmailsRequest, opts ...grpc.CallOption) (*SendEmailsResponse, error)
 	FindEmailById(ctx context.Context, in *FindEmailByIdRequest, opts ...grpc.CallOption) (*FindEmailByIdResponse, error)
 	FindEmailsByReceiver(ctx context.Context, in *FindEmailsByReceiverRequest, opts ...grpc.CallOption) (*FindEmailsByReceiverResponse, error)
+	SendBulkEmails(ctx context.Context, in *SendBulkEmailsRequest, opts ...grpc.CallOption) (*SendBulkEmailsResponse, error)
+	GetBulkEmailsProgress(ctx context.Context, in *GetBulkEmailsProgressRequest, opts ...grpc.CallOption) (*GetBulkEmailsProgressResponse, error)
+	AddSuppression(ctx context.Context, in *AddSuppressionRequest, opts ...grpc.CallOption) (*AddSuppressionResponse, error)
+	RemoveSuppression(ctx context.Context, in *RemoveSuppressionRequest, opts ...grpc.CallOption) (*RemoveSuppressionResponse, error)
+	ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error)
+	ImportSuppressions(ctx context.Context, in *ImportSuppressionsRequest, opts ...grpc.CallOption) (*ImportSuppressionsResponse, error)
+	ListParkedEmails(ctx context.Context, in *ListParkedEmailsRequest, opts ...grpc.CallOption) (*ListParkedEmailsResponse, error)
+	GetParkedEmail(ctx context.Context, in *GetParkedEmailRequest, opts ...grpc.CallOption) (*GetParkedEmailResponse, error)
+	ReplayParkedEmails(ctx context.Context, in *ReplayParkedEmailsRequest, opts ...grpc.CallOption) (*ReplayParkedEmailsResponse, error)
+	PurgeParkedEmails(ctx context.Context, in *PurgeParkedEmailsRequest, opts ...grpc.CallOption) (*PurgeParkedEmailsResponse, error)
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error)
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error)
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error)
+	UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error)
+	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error)
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	RotateTenantApiKey(ctx context.Context, in *RotateTenantApiKeyRequest, opts ...grpc.CallOption) (*RotateTenantApiKeyResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	GetEmailEvents(ctx context.Context, in *GetEmailEventsRequest, opts ...grpc.CallOption) (*GetEmailEventsResponse, error)
+	ProcessBounceReport(ctx context.Context, in *ProcessBounceReportRequest, opts ...grpc.CallOption) (*ProcessBounceReportResponse, error)
+	GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error)
 }
 
 type emailServiceClient struct {
@@ -62,6 +86,222 @@ func (c *emailServiceClient) FindEmailsByReceiver(ctx context.Context, in *FindE
 	return out, nil
 }
 
+func (c *emailServiceClient) SendBulkEmails(ctx context.Context, in *SendBulkEmailsRequest, opts ...grpc.CallOption) (*SendBulkEmailsResponse, error) {
+	out := new(SendBulkEmailsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/SendBulkEmails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetBulkEmailsProgress(ctx context.Context, in *GetBulkEmailsProgressRequest, opts ...grpc.CallOption) (*GetBulkEmailsProgressResponse, error) {
+	out := new(GetBulkEmailsProgressResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetBulkEmailsProgress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) AddSuppression(ctx context.Context, in *AddSuppressionRequest, opts ...grpc.CallOption) (*AddSuppressionResponse, error) {
+	out := new(AddSuppressionResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/AddSuppression", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) RemoveSuppression(ctx context.Context, in *RemoveSuppressionRequest, opts ...grpc.CallOption) (*RemoveSuppressionResponse, error) {
+	out := new(RemoveSuppressionResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/RemoveSuppression", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error) {
+	out := new(ListSuppressionsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ListSuppressions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ImportSuppressions(ctx context.Context, in *ImportSuppressionsRequest, opts ...grpc.CallOption) (*ImportSuppressionsResponse, error) {
+	out := new(ImportSuppressionsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ImportSuppressions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ListParkedEmails(ctx context.Context, in *ListParkedEmailsRequest, opts ...grpc.CallOption) (*ListParkedEmailsResponse, error) {
+	out := new(ListParkedEmailsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ListParkedEmails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetParkedEmail(ctx context.Context, in *GetParkedEmailRequest, opts ...grpc.CallOption) (*GetParkedEmailResponse, error) {
+	out := new(GetParkedEmailResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetParkedEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ReplayParkedEmails(ctx context.Context, in *ReplayParkedEmailsRequest, opts ...grpc.CallOption) (*ReplayParkedEmailsResponse, error) {
+	out := new(ReplayParkedEmailsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ReplayParkedEmails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) PurgeParkedEmails(ctx context.Context, in *PurgeParkedEmailsRequest, opts ...grpc.CallOption) (*PurgeParkedEmailsResponse, error) {
+	out := new(PurgeParkedEmailsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/PurgeParkedEmails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error) {
+	out := new(CreateTemplateResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error) {
+	out := new(UpdateTemplateResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/UpdateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error) {
+	out := new(DeleteTemplateResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/DeleteTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*CreateTenantResponse, error) {
+	out := new(CreateTenantResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/CreateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*UpdateTenantResponse, error) {
+	out := new(UpdateTenantResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/UpdateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*GetTenantResponse, error) {
+	out := new(GetTenantResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ListTenants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) RotateTenantApiKey(ctx context.Context, in *RotateTenantApiKeyRequest, opts ...grpc.CallOption) (*RotateTenantApiKeyResponse, error) {
+	out := new(RotateTenantApiKeyResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/RotateTenantApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetEmailEvents(ctx context.Context, in *GetEmailEventsRequest, opts ...grpc.CallOption) (*GetEmailEventsResponse, error) {
+	out := new(GetEmailEventsResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetEmailEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ProcessBounceReport(ctx context.Context, in *ProcessBounceReportRequest, opts ...grpc.CallOption) (*ProcessBounceReportResponse, error) {
+	out := new(ProcessBounceReportResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/ProcessBounceReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error) {
+	out := new(GetWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/emailService.EmailService/GetWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EmailServiceServer is the server API for EmailService service.
 // All implementations must embed UnimplementedEmailServiceServer
 // for forward compatibility
@@ -69,6 +309,30 @@ type EmailServiceServer interface {
 	SendEmails(context.Context, *SendEmailsRequest) (*SendEmailsResponse, error)
 	FindEmailById(context.Context, *FindEmailByIdRequest) (*FindEmailByIdResponse, error)
 	FindEmailsByReceiver(context.Context, *FindEmailsByReceiverRequest) (*FindEmailsByReceiverResponse, error)
+	SendBulkEmails(context.Context, *SendBulkEmailsRequest) (*SendBulkEmailsResponse, error)
+	GetBulkEmailsProgress(context.Context, *GetBulkEmailsProgressRequest) (*GetBulkEmailsProgressResponse, error)
+	AddSuppression(context.Context, *AddSuppressionRequest) (*AddSuppressionResponse, error)
+	RemoveSuppression(context.Context, *RemoveSuppressionRequest) (*RemoveSuppressionResponse, error)
+	ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error)
+	ImportSuppressions(context.Context, *ImportSuppressionsRequest) (*ImportSuppressionsResponse, error)
+	ListParkedEmails(context.Context, *ListParkedEmailsRequest) (*ListParkedEmailsResponse, error)
+	GetParkedEmail(context.Context, *GetParkedEmailRequest) (*GetParkedEmailResponse, error)
+	ReplayParkedEmails(context.Context, *ReplayParkedEmailsRequest) (*ReplayParkedEmailsResponse, error)
+	PurgeParkedEmails(context.Context, *PurgeParkedEmailsRequest) (*PurgeParkedEmailsResponse, error)
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error)
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error)
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error)
+	UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error)
+	GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error)
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	RotateTenantApiKey(context.Context, *RotateTenantApiKeyRequest) (*RotateTenantApiKeyResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	GetEmailEvents(context.Context, *GetEmailEventsRequest) (*GetEmailEventsResponse, error)
+	ProcessBounceReport(context.Context, *ProcessBounceReportRequest) (*ProcessBounceReportResponse, error)
+	GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error)
 	mustEmbedUnimplementedEmailServiceServer()
 }
 
@@ -85,6 +349,78 @@ func (UnimplementedEmailServiceServer) FindEmailById(context.Context, *FindEmail
 func (UnimplementedEmailServiceServer) FindEmailsByReceiver(context.Context, *FindEmailsByReceiverRequest) (*FindEmailsByReceiverResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindEmailsByReceiver not implemented")
 }
+func (UnimplementedEmailServiceServer) SendBulkEmails(context.Context, *SendBulkEmailsRequest) (*SendBulkEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendBulkEmails not implemented")
+}
+func (UnimplementedEmailServiceServer) GetBulkEmailsProgress(context.Context, *GetBulkEmailsProgressRequest) (*GetBulkEmailsProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBulkEmailsProgress not implemented")
+}
+func (UnimplementedEmailServiceServer) AddSuppression(context.Context, *AddSuppressionRequest) (*AddSuppressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSuppression not implemented")
+}
+func (UnimplementedEmailServiceServer) RemoveSuppression(context.Context, *RemoveSuppressionRequest) (*RemoveSuppressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSuppression not implemented")
+}
+func (UnimplementedEmailServiceServer) ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuppressions not implemented")
+}
+func (UnimplementedEmailServiceServer) ImportSuppressions(context.Context, *ImportSuppressionsRequest) (*ImportSuppressionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSuppressions not implemented")
+}
+func (UnimplementedEmailServiceServer) ListParkedEmails(context.Context, *ListParkedEmailsRequest) (*ListParkedEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListParkedEmails not implemented")
+}
+func (UnimplementedEmailServiceServer) GetParkedEmail(context.Context, *GetParkedEmailRequest) (*GetParkedEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetParkedEmail not implemented")
+}
+func (UnimplementedEmailServiceServer) ReplayParkedEmails(context.Context, *ReplayParkedEmailsRequest) (*ReplayParkedEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayParkedEmails not implemented")
+}
+func (UnimplementedEmailServiceServer) PurgeParkedEmails(context.Context, *PurgeParkedEmailsRequest) (*PurgeParkedEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeParkedEmails not implemented")
+}
+func (UnimplementedEmailServiceServer) CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (UnimplementedEmailServiceServer) GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (UnimplementedEmailServiceServer) UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTemplate not implemented")
+}
+func (UnimplementedEmailServiceServer) DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedEmailServiceServer) ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedEmailServiceServer) CreateTenant(context.Context, *CreateTenantRequest) (*CreateTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTenant not implemented")
+}
+func (UnimplementedEmailServiceServer) UpdateTenant(context.Context, *UpdateTenantRequest) (*UpdateTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenant not implemented")
+}
+func (UnimplementedEmailServiceServer) GetTenant(context.Context, *GetTenantRequest) (*GetTenantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenant not implemented")
+}
+func (UnimplementedEmailServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedEmailServiceServer) RotateTenantApiKey(context.Context, *RotateTenantApiKeyRequest) (*RotateTenantApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateTenantApiKey not implemented")
+}
+func (UnimplementedEmailServiceServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedEmailServiceServer) GetEmailEvents(context.Context, *GetEmailEventsRequest) (*GetEmailEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmailEvents not implemented")
+}
+func (UnimplementedEmailServiceServer) ProcessBounceReport(context.Context, *ProcessBounceReportRequest) (*ProcessBounceReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessBounceReport not implemented")
+}
+func (UnimplementedEmailServiceServer) GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveries not implemented")
+}
 func (UnimplementedEmailServiceServer) mustEmbedUnimplementedEmailServiceServer() {}
 
 // UnsafeEmailServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -152,24 +488,552 @@ func _EmailService_FindEmailsByReceiver_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
-// EmailService_ServiceDesc is the grpc.ServiceDesc for EmailService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var EmailService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "emailService.EmailService",
-	HandlerType: (*EmailServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SendEmails",
-			Handler:    _EmailService_SendEmails_Handler,
-		},
-		{
-			MethodName: "FindEmailById",
-			Handler:    _EmailService_FindEmailById_Handler,
-		},
-		{
-			MethodName: "FindEmailsByReceiver",
-			Handler:    _EmailService_FindEmailsByReceiver_Handler,
+func _EmailService_SendBulkEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendBulkEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).SendBulkEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/SendBulkEmails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).SendBulkEmails(ctx, req.(*SendBulkEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetBulkEmailsProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBulkEmailsProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetBulkEmailsProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetBulkEmailsProgress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetBulkEmailsProgress(ctx, req.(*GetBulkEmailsProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_AddSuppression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSuppressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).AddSuppression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/AddSuppression",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).AddSuppression(ctx, req.(*AddSuppressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_RemoveSuppression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSuppressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).RemoveSuppression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/RemoveSuppression",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).RemoveSuppression(ctx, req.(*RemoveSuppressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ListSuppressions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuppressionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ListSuppressions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ListSuppressions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ListSuppressions(ctx, req.(*ListSuppressionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ImportSuppressions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSuppressionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ImportSuppressions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ImportSuppressions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ImportSuppressions(ctx, req.(*ImportSuppressionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ListParkedEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListParkedEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ListParkedEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ListParkedEmails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ListParkedEmails(ctx, req.(*ListParkedEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetParkedEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetParkedEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetParkedEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetParkedEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetParkedEmail(ctx, req.(*GetParkedEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ReplayParkedEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayParkedEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ReplayParkedEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ReplayParkedEmails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ReplayParkedEmails(ctx, req.(*ReplayParkedEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_PurgeParkedEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeParkedEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).PurgeParkedEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/PurgeParkedEmails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).PurgeParkedEmails(ctx, req.(*PurgeParkedEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_UpdateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).UpdateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/UpdateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).UpdateTemplate(ctx, req.(*UpdateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/DeleteTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_CreateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).CreateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/CreateTenant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).CreateTenant(ctx, req.(*CreateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_UpdateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).UpdateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/UpdateTenant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).UpdateTenant(ctx, req.(*UpdateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetTenant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetTenant(ctx, req.(*GetTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ListTenants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ListTenants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ListTenants(ctx, req.(*ListTenantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_RotateTenantApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateTenantApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).RotateTenantApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/RotateTenantApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).RotateTenantApiKey(ctx, req.(*RotateTenantApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetEmailEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmailEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetEmailEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetEmailEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetEmailEvents(ctx, req.(*GetEmailEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ProcessBounceReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessBounceReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ProcessBounceReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/ProcessBounceReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ProcessBounceReport(ctx, req.(*ProcessBounceReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/emailService.EmailService/GetWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetWebhookDeliveries(ctx, req.(*GetWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmailService_ServiceDesc is the grpc.ServiceDesc for EmailService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmailService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "emailService.EmailService",
+	HandlerType: (*EmailServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendEmails",
+			Handler:    _EmailService_SendEmails_Handler,
+		},
+		{
+			MethodName: "FindEmailById",
+			Handler:    _EmailService_FindEmailById_Handler,
+		},
+		{
+			MethodName: "FindEmailsByReceiver",
+			Handler:    _EmailService_FindEmailsByReceiver_Handler,
+		},
+		{
+			MethodName: "SendBulkEmails",
+			Handler:    _EmailService_SendBulkEmails_Handler,
+		},
+		{
+			MethodName: "GetBulkEmailsProgress",
+			Handler:    _EmailService_GetBulkEmailsProgress_Handler,
+		},
+		{
+			MethodName: "AddSuppression",
+			Handler:    _EmailService_AddSuppression_Handler,
+		},
+		{
+			MethodName: "RemoveSuppression",
+			Handler:    _EmailService_RemoveSuppression_Handler,
+		},
+		{
+			MethodName: "ListSuppressions",
+			Handler:    _EmailService_ListSuppressions_Handler,
+		},
+		{
+			MethodName: "ImportSuppressions",
+			Handler:    _EmailService_ImportSuppressions_Handler,
+		},
+		{
+			MethodName: "ListParkedEmails",
+			Handler:    _EmailService_ListParkedEmails_Handler,
+		},
+		{
+			MethodName: "GetParkedEmail",
+			Handler:    _EmailService_GetParkedEmail_Handler,
+		},
+		{
+			MethodName: "ReplayParkedEmails",
+			Handler:    _EmailService_ReplayParkedEmails_Handler,
+		},
+		{
+			MethodName: "PurgeParkedEmails",
+			Handler:    _EmailService_PurgeParkedEmails_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _EmailService_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _EmailService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "UpdateTemplate",
+			Handler:    _EmailService_UpdateTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _EmailService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _EmailService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "CreateTenant",
+			Handler:    _EmailService_CreateTenant_Handler,
+		},
+		{
+			MethodName: "UpdateTenant",
+			Handler:    _EmailService_UpdateTenant_Handler,
+		},
+		{
+			MethodName: "GetTenant",
+			Handler:    _EmailService_GetTenant_Handler,
+		},
+		{
+			MethodName: "ListTenants",
+			Handler:    _EmailService_ListTenants_Handler,
+		},
+		{
+			MethodName: "RotateTenantApiKey",
+			Handler:    _EmailService_RotateTenantApiKey_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _EmailService_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetEmailEvents",
+			Handler:    _EmailService_GetEmailEvents_Handler,
+		},
+		{
+			MethodName: "ProcessBounceReport",
+			Handler:    _EmailService_ProcessBounceReport_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveries",
+			Handler:    _EmailService_GetWebhookDeliveries_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,13 @@
+package email
+
+import (
+	"context"
+	"time"
+)
+
+// Send rate limiter interface, global and recipients domains tokens are taken once per email,
+// account tokens are taken from every provider trying to send it
+type RateLimiter interface {
+	Take(ctx context.Context, recipients []string) (time.Duration, error)
+	TakeAccount(ctx context.Context, account string) (time.Duration, error)
+}
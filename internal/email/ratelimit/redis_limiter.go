@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"rmq_service/config"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultKeyPrefix = "emails-rate-limit"
+)
+
+// Check token buckets of all given keys and take one token from each only when every bucket has it,
+// otherwise return milliseconds to wait for the slowest bucket.
+// Redis time is used, so replicas with skewed clocks share the same buckets.
+var takeTokensScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local wait = 0
+local tokens = {}
+
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[i * 2 - 1])
+	local burst = tonumber(ARGV[i * 2])
+	local state = redis.call("HMGET", key, "tokens", "ts")
+	local available = tonumber(state[1]) or burst
+	local ts = tonumber(state[2]) or now
+
+	available = math.min(burst, available + math.max(0, now - ts) * rate / 1000)
+	tokens[i] = available
+	if available < 1 then
+		wait = math.max(wait, math.ceil((1 - available) * 1000 / rate))
+	end
+end
+
+if wait > 0 then
+	return wait
+end
+
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[i * 2 - 1])
+	local burst = tonumber(ARGV[i * 2])
+	redis.call("HSET", key, "tokens", tokens[i] - 1, "ts", now)
+	redis.call("PEXPIRE", key, math.ceil(burst * 1000 / rate) + 1000)
+end
+
+return 0
+`)
+
+// Send rate limit exceeded, email must be delayed
+type LimitExceededError struct {
+	Delay time.Duration
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("send rate limit exceeded, retry in %s", e.Delay)
+}
+
+// Token bucket
+type bucket struct {
+	key 	string
+	limit config.RateLimit
+}
+
+// Redis token bucket rate limiter shared by all replicas
+type RedisLimiter struct {
+	redisClient *redis.Client
+	cfg 				config.RateLimits
+	domains 		map[string]config.RateLimit
+}
+
+// Redis rate limiter constructor
+func NewRedisLimiter(redisClient *redis.Client, cfg config.RateLimits) *RedisLimiter {
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = defaultKeyPrefix
+	}
+
+	domains := make(map[string]config.RateLimit, len(cfg.Domains))
+	for _, d := range cfg.Domains {
+		domains[strings.ToLower(d.Domain)] = d.RateLimit
+	}
+
+	return &RedisLimiter{redisClient: redisClient, cfg: cfg, domains: domains}
+}
+
+// Take send tokens of the global and recipients domains limits.
+// Returns delay until all tokens are available, nothing is taken when delay is not zero.
+func (l *RedisLimiter) Take(ctx context.Context, recipients []string) (time.Duration, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RedisLimiter.Take")
+	defer span.Finish()
+
+	return l.take(ctx, l.buckets(recipients))
+}
+
+// Take send token of the provider account limit, returns delay until the token is available
+func (l *RedisLimiter) TakeAccount(ctx context.Context, account string) (time.Duration, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RedisLimiter.TakeAccount")
+	defer span.Finish()
+
+	buckets := make([]bucket, 0, 1)
+	if l.cfg.Account.Rate > 0 {
+		buckets = append(buckets, l.bucket("account", account, l.cfg.Account))
+	}
+	return l.take(ctx, buckets)
+}
+
+// Take one token from each bucket only when every bucket has it
+func (l *RedisLimiter) take(ctx context.Context, buckets []bucket) (time.Duration, error) {
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(buckets))
+	args := make([]interface{}, 0, len(buckets)*2)
+	for _, b := range buckets {
+		keys = append(keys, b.key)
+		args = append(args, b.limit.Rate, burst(b.limit))
+	}
+
+	wait, err := takeTokensScript.Run(ctx, l.redisClient, keys, args...).Int64()
+	if err != nil {
+		return 0, errors.Wrap(err, "redis takeTokensScript.Run")
+	}
+
+	delay := time.Duration(wait) * time.Millisecond
+	if maxDelay := l.cfg.MaxDelay; maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay, nil
+}
+
+// Get global and recipients domains buckets of enabled limits
+func (l *RedisLimiter) buckets(recipients []string) []bucket {
+	buckets := make([]bucket, 0, len(recipients)+1)
+	add := func(kind, name string, limit config.RateLimit) {
+		if limit.Rate <= 0 {
+			return
+		}
+		buckets = append(buckets, l.bucket(kind, name, limit))
+	}
+
+	add("global", "all", l.cfg.Global)
+
+	seen := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		domain := strings.ToLower(recipient[strings.LastIndex(recipient, "@")+1:])
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		limit, ok := l.domains[domain]
+		if !ok {
+			limit = l.cfg.Domain
+		}
+		add("domain", domain, limit)
+	}
+
+	return buckets
+}
+
+// Get bucket of the limit, keys share the hash tag, so script works in Redis Cluster
+func (l *RedisLimiter) bucket(kind, name string, limit config.RateLimit) bucket {
+	return bucket{key: fmt.Sprintf("{%s}:%s:%s", l.cfg.KeyPrefix, kind, name), limit: limit}
+}
+
+// Get bucket size, at least one second of the rate
+func burst(limit config.RateLimit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return int(math.Max(1, math.Ceil(limit.Rate)))
+}
@@ -0,0 +1,200 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"rmq_service/config"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis limiter of the test, the token bucket script needs a real Redis set by REDIS_ADDR
+func newTestLimiter(t *testing.T, cfg config.RateLimits) (*RedisLimiter, *redis.Client) {
+	t.Helper()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("redis is not available: %v", err)
+	}
+
+	cfg.KeyPrefix = fmt.Sprintf("test-rate-limit-%s-%d", t.Name(), time.Now().UnixNano())
+	limiter := NewRedisLimiter(client, cfg)
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, "{"+cfg.KeyPrefix+"}:*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+	return limiter, client
+}
+
+func TestRedisLimiterTakeBurstThenDelay(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimits{
+		Account: config.RateLimit{Rate: 1, Burst: 2},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		delay, err := limiter.TakeAccount(ctx, "gmail")
+		if err != nil {
+			t.Fatalf("Take %d: %v", i, err)
+		}
+		if delay != 0 {
+			t.Fatalf("Take %d: delay %s, want none within burst", i, delay)
+		}
+	}
+
+	delay, err := limiter.TakeAccount(ctx, "gmail")
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if delay <= 0 || delay > time.Second {
+		t.Fatalf("delay %s, want (0, 1s]", delay)
+	}
+}
+
+func TestRedisLimiterTakeRefills(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimits{
+		Account: config.RateLimit{Rate: 20, Burst: 1},
+	})
+	ctx := context.Background()
+
+	if delay, err := limiter.TakeAccount(ctx, "gmail"); err != nil || delay != 0 {
+		t.Fatalf("first Take: delay %s, err %v", delay, err)
+	}
+
+	delay, err := limiter.TakeAccount(ctx, "gmail")
+	if err != nil {
+		t.Fatalf("second Take: %v", err)
+	}
+	if delay <= 0 {
+		t.Fatalf("second Take: no delay on empty bucket")
+	}
+
+	time.Sleep(delay + 10*time.Millisecond)
+	if delay, err := limiter.TakeAccount(ctx, "gmail"); err != nil || delay != 0 {
+		t.Fatalf("Take after refill: delay %s, err %v", delay, err)
+	}
+}
+
+func TestRedisLimiterTakeAllOrNothing(t *testing.T) {
+	limiter, client := newTestLimiter(t, config.RateLimits{
+		Global: config.RateLimit{Rate: 1, Burst: 5},
+		Domain: config.RateLimit{Rate: 1, Burst: 1},
+	})
+	ctx := context.Background()
+
+	if delay, err := limiter.Take(ctx, []string{"to@example.com"}); err != nil || delay != 0 {
+		t.Fatalf("first Take: delay %s, err %v", delay, err)
+	}
+
+	// domain bucket is empty, so the global bucket must keep its tokens
+	delay, err := limiter.Take(ctx, []string{"other@example.com"})
+	if err != nil {
+		t.Fatalf("second Take: %v", err)
+	}
+	if delay <= 0 {
+		t.Fatalf("second Take: no delay on empty domain bucket")
+	}
+
+	globalKey := fmt.Sprintf("{%s}:global:all", limiter.cfg.KeyPrefix)
+	tokens, err := client.HGet(ctx, globalKey, "tokens").Float64()
+	if err != nil {
+		t.Fatalf("HGet %s: %v", globalKey, err)
+	}
+	if tokens < 3.9 {
+		t.Fatalf("global tokens %v, want 4 left after one take", tokens)
+	}
+}
+
+func TestRedisLimiterTakeAccountsApart(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimits{
+		Account: config.RateLimit{Rate: 1, Burst: 1},
+	})
+	ctx := context.Background()
+
+	if delay, err := limiter.TakeAccount(ctx, "gmail"); err != nil || delay != 0 {
+		t.Fatalf("Take gmail: delay %s, err %v", delay, err)
+	}
+	if delay, err := limiter.TakeAccount(ctx, "mailgun"); err != nil || delay != 0 {
+		t.Fatalf("Take mailgun: delay %s, err %v", delay, err)
+	}
+}
+
+func TestRedisLimiterTakeMaxDelay(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimits{
+		MaxDelay: 100 * time.Millisecond,
+		Account: 	config.RateLimit{Rate: 0.1, Burst: 1},
+	})
+	ctx := context.Background()
+
+	if _, err := limiter.TakeAccount(ctx, "gmail"); err != nil {
+		t.Fatalf("first Take: %v", err)
+	}
+
+	delay, err := limiter.TakeAccount(ctx, "gmail")
+	if err != nil {
+		t.Fatalf("second Take: %v", err)
+	}
+	if delay != 100*time.Millisecond {
+		t.Fatalf("delay %s, want capped to 100ms", delay)
+	}
+}
+
+func TestRedisLimiterBuckets(t *testing.T) {
+	limiter := NewRedisLimiter(nil, config.RateLimits{
+		Global: 	config.RateLimit{Rate: 50},
+		Account: 	config.RateLimit{Rate: 1},
+		Domain: 	config.RateLimit{Rate: 10, Burst: 20},
+		Domains: 	[]config.DomainRateLimit{{Domain: "Gmail.com", RateLimit: config.RateLimit{Rate: 5}}},
+	})
+
+	buckets := limiter.buckets([]string{"a@gmail.com", "b@GMAIL.com", "c@example.com"})
+
+	want := map[string]config.RateLimit{
+		"{emails-rate-limit}:global:all": 					{Rate: 50},
+		"{emails-rate-limit}:domain:gmail.com": 		{Rate: 5},
+		"{emails-rate-limit}:domain:example.com": 	{Rate: 10, Burst: 20},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("buckets %+v, want %d buckets without account limit", buckets, len(want))
+	}
+	for _, b := range buckets {
+		limit, ok := want[b.key]
+		if !ok {
+			t.Fatalf("unexpected bucket %s", b.key)
+		}
+		if b.limit != limit {
+			t.Fatalf("bucket %s limit %+v, want %+v", b.key, b.limit, limit)
+		}
+	}
+}
+
+func TestBurst(t *testing.T) {
+	tests := []struct {
+		limit config.RateLimit
+		want 	int
+	}{
+		{config.RateLimit{Rate: 10, Burst: 3}, 3},
+		{config.RateLimit{Rate: 2.5}, 3},
+		{config.RateLimit{Rate: 0.1}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := burst(tt.limit); got != tt.want {
+			t.Errorf("burst(%+v) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Attachments AWS S3 repository
+type AttachmentsAWSRepository struct {
+	client *minio.Client
+	bucket string
+}
+
+// Attachments AWS S3 repository constructor
+func NewAttachmentsAWSRepository(client *minio.Client, bucket string) *AttachmentsAWSRepository {
+	return &AttachmentsAWSRepository{client: client, bucket: bucket}
+}
+
+// Upload attachment content
+func (r *AttachmentsAWSRepository) PutAttachment(ctx context.Context, key, contentType string, content []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsAWSRepository.PutAttachment")
+	defer span.Finish()
+
+	if _, err := r.client.PutObject(
+		ctx,
+		r.bucket,
+		key,
+		bytes.NewReader(content),
+		int64(len(content)),
+		minio.PutObjectOptions{ContentType: contentType},
+	); err != nil {
+		return errors.Wrap(err, "client.PutObject")
+	}
+
+	return nil
+}
+
+// Download attachment content
+func (r *AttachmentsAWSRepository) GetAttachment(ctx context.Context, key string) ([]byte, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsAWSRepository.GetAttachment")
+	defer span.Finish()
+
+	object, err := r.client.GetObject(ctx, r.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "client.GetObject")
+	}
+	defer object.Close()
+
+	content, err := io.ReadAll(object)
+	if err != nil {
+		return nil, errors.Wrap(err, "io.ReadAll")
+	}
+
+	return content, nil
+}
+
+// Get stored attachment size
+func (r *AttachmentsAWSRepository) GetAttachmentSize(ctx context.Context, key string) (int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsAWSRepository.GetAttachmentSize")
+	defer span.Finish()
+
+	info, err := r.client.StatObject(ctx, r.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "client.StatObject")
+	}
+
+	return info.Size, nil
+}
+
+// Delete all attachments under the key prefix
+func (r *AttachmentsAWSRepository) DeleteAttachments(ctx context.Context, prefix string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsAWSRepository.DeleteAttachments")
+	defer span.Finish()
+
+	objects := r.client.ListObjects(ctx, r.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for removeErr := range r.client.RemoveObjects(ctx, r.bucket, objects, minio.RemoveObjectsOptions{}) {
+		if removeErr.Err != nil {
+			return errors.Wrap(removeErr.Err, "client.RemoveObjects")
+		}
+	}
+
+	return nil
+}
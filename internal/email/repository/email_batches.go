@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"rmq_service/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Create emails batch of the tenant
+func (r *EmailsRepository) CreateEmailBatch(ctx context.Context, tenantID *uuid.UUID, total int) (*models.EmailBatch, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.CreateEmailBatch")
+	defer span.Finish()
+
+	batch := &models.EmailBatch{}
+	if err := r.db.QueryRowxContext(ctx, createEmailBatchQuery, total, tenantID).StructScan(batch); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	return batch, nil
+}
+
+// Find emails batch of the tenant with emails count by status
+func (r *EmailsRepository) FindEmailBatchProgress(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*models.EmailBatchProgress, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailBatchProgress")
+	defer span.Finish()
+
+	progress := &models.EmailBatchProgress{Statuses: make(map[string]uint64)}
+	if err := r.db.QueryRowxContext(ctx, findEmailBatchQuery, id, tenantID).StructScan(&progress.EmailBatch); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	rows, err := r.db.QueryContext(ctx, countBatchEmailsByStatusQuery, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.QueryContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count uint64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, errors.Wrap(err, "rows.Scan")
+		}
+		progress.Statuses[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows.Err")
+	}
+
+	return progress, nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"log"
 	"rmq_service/internal/models"
+	webhookRepository "rmq_service/internal/webhook/repository"
+	"rmq_service/pkg/grpc_errors"
 	"rmq_service/pkg/utils"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -12,14 +17,22 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	defaultIdempotencyWindow = 24 * time.Hour
+)
+
 // Emails Repository
 type EmailsRepository struct {
-	db *sqlx.DB
+	db 								*sqlx.DB
+	idempotencyWindow time.Duration
 }
 
 // Images AWS repository constructor
-func NewEmailsRepository(db *sqlx.DB) *EmailsRepository {
-	return &EmailsRepository{db: db}
+func NewEmailsRepository(db *sqlx.DB, idempotencyWindow time.Duration) *EmailsRepository {
+	if idempotencyWindow <= 0 {
+		idempotencyWindow = defaultIdempotencyWindow
+	}
+	return &EmailsRepository{db: db, idempotencyWindow: idempotencyWindow}
 }
 
 // Create email
@@ -27,8 +40,30 @@ func (r *EmailsRepository) CreateEmail(ctx context.Context, email *models.Email)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.CreateEmail")
 	defer span.Finish()
 
-	var id uuid.UUID
-	if err := r.db.QueryRowContext(
+	if email.Status == "" {
+		email.Status = models.EmailStatusAccepted
+	}
+
+	if email.Headers == nil {
+		email.Headers = map[string]string{}
+	}
+
+	if email.Priority == "" {
+		email.Priority = models.EmailPriorityNormal
+	}
+
+	headers, err := json.Marshal(email.Headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(
 		ctx,
 		createEmailQuery,
 		email.GetToString(),
@@ -36,51 +71,345 @@ func (r *EmailsRepository) CreateEmail(ctx context.Context, email *models.Email)
 		email.Subject,
 		email.Body,
 		email.ContentType,
-	).Scan(&id); err != nil {
-		log.Fatalf("repository::QueryRowContext(): %v", err)
-		return nil, err
+		email.SendAt,
+		email.Status,
+		email.StatusReason,
+		email.GetCcString(),
+		email.GetBccString(),
+		email.ReplyTo,
+		headers,
+		email.TextBody,
+		email.BatchID,
+		email.Category,
+		email.Priority,
+		email.TenantID,
+		email.WebhookURL,
+	).Scan(&email.EmailID, &email.CreatedAt, &email.UpdatedAt); err != nil {
+		return nil, errors.Wrap(err, "tx.QueryRowContext")
+	}
+
+	if _, err := tx.ExecContext(ctx, createStatusTransitionQuery, email.EmailID, email.Status, email.StatusReason); err != nil {
+		return nil, errors.Wrap(err, "tx.ExecContext")
+	}
+
+	if email.IdempotencyKey != "" {
+		if err := r.reserveIdempotencyKey(ctx, tx, email); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "tx.Commit")
 	}
 
-	email.EmailID = id
 	return email, nil
 }
 
-// FindEmailById
-func (r *EmailsRepository) FindEmailById(ctx context.Context, id uuid.UUID) (*models.Email, error) {
+// Bind idempotency key to the new email, unless the key was used inside the window.
+// On duplicate the original email id is set and ErrEmailExists is returned.
+func (r *EmailsRepository) reserveIdempotencyKey(ctx context.Context, tx *sqlx.Tx, email *models.Email) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(
+		ctx,
+		reserveIdempotencyKeyQuery,
+		email.IdempotencyKey,
+		email.EmailID,
+		time.Now().Add(-r.idempotencyWindow),
+		email.TenantID,
+	).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrap(err, "tx.QueryRowContext")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		return errors.Wrap(err, "tx.Rollback")
+	}
+
+	if err := r.db.QueryRowContext(ctx, findIdempotencyKeyEmailQuery, email.IdempotencyKey, email.TenantID).Scan(&id); err != nil {
+		return errors.Wrap(err, "db.QueryRowContext")
+	}
+
+	email.EmailID = id
+	return grpc_errors.ErrEmailExists
+}
+
+// Check if email of the tenant with idempotency key was already sent inside the window
+func (r *EmailsRepository) IsIdempotencyKeyProcessed(ctx context.Context, tenantID *uuid.UUID, key string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.IsIdempotencyKeyProcessed")
+	defer span.Finish()
+
+	var processed bool
+	if err := r.db.QueryRowContext(
+		ctx,
+		isIdempotencyKeyProcessedQuery,
+		key,
+		time.Now().Add(-r.idempotencyWindow),
+		tenantID,
+	).Scan(&processed); err != nil {
+		return false, errors.Wrap(err, "db.QueryRowContext")
+	}
+
+	return processed, nil
+}
+
+// Mark idempotency key of the tenant as processed
+func (r *EmailsRepository) MarkIdempotencyKeyProcessed(ctx context.Context, tenantID *uuid.UUID, key string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.MarkIdempotencyKeyProcessed")
+	defer span.Finish()
+
+	if _, err := r.db.ExecContext(ctx, markIdempotencyKeyProcessedQuery, key, tenantID); err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	return nil
+}
+
+// Update email status and record the transition. Status webhook delivery is added to the outbox
+// in the same transaction, so the webhook is posted for every committed status.
+func (r *EmailsRepository) UpdateEmailStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status,
+	reason string,
+	webhookDelivery *models.WebhookDelivery,
+) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.UpdateEmailStatus")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if err := r.updateEmailStatusTx(ctx, tx, id, status, reason); err != nil {
+		return err
+	}
+
+	if webhookDelivery != nil {
+		if _, err := webhookRepository.CreateWebhookDeliveryTx(ctx, tx, webhookDelivery); err != nil {
+			return errors.Wrap(err, "webhookRepository.CreateWebhookDeliveryTx")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Record another sending attempt of the email left in sending status, e.g. by a crash mid-send.
+// False is returned when the email is in any other status.
+func (r *EmailsRepository) ResumeEmailSending(ctx context.Context, id uuid.UUID) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.ResumeEmailSending")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var current string
+	if err := tx.QueryRowContext(ctx, lockEmailStatusQuery, id).Scan(&current); err != nil {
+		return false, errors.Wrap(err, "tx.QueryRowContext")
+	}
+
+	if current != models.EmailStatusSending {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, createStatusTransitionQuery, id, models.EmailStatusSending, "redelivered"); err != nil {
+		return false, errors.Wrap(err, "tx.ExecContext")
+	}
+
+	return true, errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Update email status inside the transaction
+func (r *EmailsRepository) updateEmailStatusTx(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status, reason string) error {
+	var current string
+	if err := tx.QueryRowContext(ctx, lockEmailStatusQuery, id).Scan(&current); err != nil {
+		return errors.Wrap(err, "tx.QueryRowContext")
+	}
+
+	if !models.CanTransitEmailStatus(current, status) {
+		return errors.Wrapf(grpc_errors.ErrInvalidEmailStatus, "%s -> %s", current, status)
+	}
+
+	if _, err := tx.ExecContext(ctx, updateEmailStatusQuery, id, status, reason); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	if _, err := tx.ExecContext(ctx, createStatusTransitionQuery, id, status, reason); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	return nil
+}
+
+// Record provider which sent the email and its message id
+func (r *EmailsRepository) UpdateEmailProvider(ctx context.Context, id uuid.UUID, provider, providerMessageID string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.UpdateEmailProvider")
+	defer span.Finish()
+
+	if _, err := r.db.ExecContext(ctx, updateEmailProviderQuery, id, provider, providerMessageID); err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	return nil
+}
+
+// Create email event, false is returned when the provider event is already recorded
+func (r *EmailsRepository) CreateEmailEvent(ctx context.Context, event *models.EmailEvent) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.CreateEmailEvent")
+	defer span.Finish()
+
+	if err := r.db.QueryRowContext(
+		ctx,
+		createEmailEventQuery,
+		event.EmailID,
+		event.Type,
+		event.URL,
+		event.UserAgent,
+		event.IP,
+		event.Recipient,
+		event.Reason,
+		event.ProviderEventID,
+	).Scan(&event.EventID, &event.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "db.QueryRowContext")
+	}
+
+	return true, nil
+}
+
+// Find email events
+func (r *EmailsRepository) FindEmailEvents(ctx context.Context, id uuid.UUID) ([]*models.EmailEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailEvents")
+	defer span.Finish()
+
+	events := make([]*models.EmailEvent, 0)
+	if err := r.db.SelectContext(ctx, &events, findEmailEventsQuery, id); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return events, nil
+}
+
+// Find all recipients of the email of any tenant
+func (r *EmailsRepository) FindEmailRecipients(ctx context.Context, id uuid.UUID) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailRecipients")
+	defer span.Finish()
+
+	var to, cc, bcc string
+	if err := r.db.QueryRowContext(ctx, findEmailRecipientsQuery, id).Scan(&to, &cc, &bcc); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowContext")
+	}
+
+	email := &models.Email{}
+	email.SetToFromString(to)
+	email.SetCcFromString(cc)
+	email.SetBccFromString(bcc)
+	return email.GetRecipients(), nil
+}
+
+// Find id of the email by Message-ID the provider sent it with
+func (r *EmailsRepository) FindEmailIdByMessageID(ctx context.Context, messageID string) (uuid.UUID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailIdByMessageID")
+	defer span.Finish()
+
+	var id uuid.UUID
+	if err := r.db.QueryRowContext(ctx, findEmailIdByMessageIdQuery, messageID).Scan(&id); err != nil {
+		return uuid.Nil, errors.Wrap(err, "db.QueryRowContext")
+	}
+
+	return id, nil
+}
+
+// Find email status transitions
+func (r *EmailsRepository) FindEmailStatusTransitions(ctx context.Context, id uuid.UUID) ([]*models.EmailStatusTransition, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailStatusTransitions")
+	defer span.Finish()
+
+	transitions := make([]*models.EmailStatusTransition, 0)
+	if err := r.db.SelectContext(ctx, &transitions, findStatusTransitionsQuery, id); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return transitions, nil
+}
+
+// Find email of the tenant by id, nil tenant id stands for the default tenant
+func (r *EmailsRepository) FindEmailById(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*models.Email, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailById")
 	defer span.Finish()
 
-	var to string
+	// email of another tenant is not found as well
+	return r.findEmail(ctx, findEmailByIdQuery, id, tenantID)
+}
+
+// Find email of any tenant by id, e.g. the email of the provider delivery event
+func (r *EmailsRepository) FindEmailByIdOfAnyTenant(ctx context.Context, id uuid.UUID) (*models.Email, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailByIdOfAnyTenant")
+	defer span.Finish()
+
+	return r.findEmail(ctx, findEmailByIdOfAnyTenantQuery, id)
+}
+
+func (r *EmailsRepository) findEmail(ctx context.Context, query string, args ...interface{}) (*models.Email, error) {
+	var to, cc string
+	var headers []byte
 	email := &models.Email{}
 
-	if err := r.db.QueryRowContext(ctx, findEmailByIdQuery, id).Scan(
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(
 		&email.EmailID,
 		&to,
 		&email.From,
 		&email.Subject,
 		&email.Body,
 		&email.ContentType,
+		&email.SendAt,
+		&email.Status,
+		&email.StatusReason,
 		&email.CreatedAt,
+		&email.UpdatedAt,
+		&cc,
+		&email.ReplyTo,
+		&headers,
+		&email.TextBody,
+		&email.Category,
+		&email.Provider,
+		&email.ProviderMessageID,
+		&email.Priority,
+		&email.TenantID,
+		&email.WebhookURL,
 	); err != nil {
-		log.Fatalf("repository::FindEmailById: %v", err)
-		return nil, err
+		return nil, errors.Wrap(err, "db.QueryRowContext")
 	}
 
 	email.SetToFromString(to)
+	email.SetCcFromString(cc)
+	if err := json.Unmarshal(headers, &email.Headers); err != nil {
+		return nil, errors.Wrap(err, "json.Unmarshal")
+	}
 
 	return email, nil
 }
 
-// FindEmailsByReceiver
+// Find emails of the tenant by receiver, nil tenant id stands for the default tenant
 func (r *EmailsRepository) FindEmailsByReceiver(
 	ctx context.Context, 
+	tenantID *uuid.UUID,
 	to string, 
 	query *utils.PaginationQuery) (list *models.EmailsList, err error) {
 		span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.FindEmailsByReceiver")
 		defer span.Finish()
 
 		var totalCount uint64
-		if err := r.db.QueryRowContext(ctx ,totalCountQuery, to).Scan(&totalCount); err != nil {
+		if err := r.db.QueryRowContext(ctx ,totalCountQuery, to, tenantID).Scan(&totalCount); err != nil {
 			log.Fatalf("EmailsRepository.FindEmailsByReceiver QueryRowContext(): %v", err)
 			return nil, err
 		}
@@ -89,7 +418,7 @@ func (r *EmailsRepository) FindEmailsByReceiver(
 			return &models.EmailsList{Emails: []*models.Email{}}, nil
 		}
 
-		rows, err := r.db.QueryxContext(ctx, findEmailByReceiverQuery, to, query.GetOffset(), query.GetLimit())
+		rows, err := r.db.QueryxContext(ctx, findEmailByReceiverQuery, to, tenantID, query.GetOffset(), query.GetLimit())
 		if err != nil {
 			log.Fatalf("EmailsRepository.FindEmailsByReceiver QueryxContext(): %v", err)
 			return nil, err
@@ -107,7 +436,8 @@ func (r *EmailsRepository) FindEmailsByReceiver(
 
 		emails := make([]*models.Email, 0, query.GetSize())
 		for rows.Next() {
-			var mailTo string
+			var mailTo, cc string
+			var headers []byte
 			email := &models.Email{}
 
 			if err := rows.Scan(
@@ -117,12 +447,30 @@ func (r *EmailsRepository) FindEmailsByReceiver(
 				&email.Subject,
 				&email.Body,
 				&email.ContentType,
+				&email.SendAt,
+				&email.Status,
+				&email.StatusReason,
 				&email.CreatedAt,
+				&email.UpdatedAt,
+				&cc,
+				&email.ReplyTo,
+				&headers,
+				&email.TextBody,
+				&email.Category,
+				&email.Provider,
+				&email.ProviderMessageID,
+				&email.Priority,
+				&email.TenantID,
+				&email.WebhookURL,
 			); err != nil {
 				return nil, errors.Wrap(err, "rows.Scan")
 			}
 
 			email.SetToFromString(mailTo)
+			email.SetCcFromString(cc)
+			if err := json.Unmarshal(headers, &email.Headers); err != nil {
+				return nil, errors.Wrap(err, "json.Unmarshal")
+			}
 			emails = append(emails, email)
 		}
 
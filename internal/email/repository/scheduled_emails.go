@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Store email in the scheduler and mark it as scheduled
+func (r *EmailsRepository) CreateScheduledEmail(ctx context.Context, scheduled *models.ScheduledEmail) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.CreateScheduledEmail")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		createScheduledEmailQuery,
+		scheduled.EmailID,
+		scheduled.SendAt,
+		scheduled.Payload,
+		scheduled.ContentType,
+		scheduled.MessageID,
+		scheduled.Priority,
+	); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	if err := r.updateEmailStatusTx(ctx, tx, scheduled.EmailID, models.EmailStatusScheduled, ""); err != nil {
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Lock due scheduled emails for the lease time, so other replicas skip them.
+// Emails which are not completed before the lease ends are claimed again.
+func (r *EmailsRepository) ClaimDueScheduledEmails(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*models.ScheduledEmail, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.ClaimDueScheduledEmails")
+	defer span.Finish()
+
+	scheduled := make([]*models.ScheduledEmail, 0, limit)
+	if err := r.db.SelectContext(
+		ctx,
+		&scheduled,
+		claimDueScheduledEmailsQuery,
+		limit,
+		time.Now().Add(lease),
+	); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return scheduled, nil
+}
+
+// Remove dispatched email from the scheduler and mark it as queued
+func (r *EmailsRepository) CompleteScheduledEmail(ctx context.Context, id uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.CompleteScheduledEmail")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, deleteScheduledEmailQuery, id); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	if err := r.updateEmailStatusTx(ctx, tx, id, models.EmailStatusQueued, ""); err != nil {
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Return completed email to the scheduler when it was not published, only queued email is rescheduled
+func (r *EmailsRepository) RescheduleEmail(ctx context.Context, scheduled *models.ScheduledEmail) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsRepository.RescheduleEmail")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var current string
+	if err := tx.QueryRowContext(ctx, lockEmailStatusQuery, scheduled.EmailID).Scan(&current); err != nil {
+		return errors.Wrap(err, "tx.QueryRowContext")
+	}
+
+	if current != models.EmailStatusQueued {
+		return errors.Wrapf(grpc_errors.ErrInvalidEmailStatus, "%s -> %s", current, models.EmailStatusScheduled)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		createScheduledEmailQuery,
+		scheduled.EmailID,
+		scheduled.SendAt,
+		scheduled.Payload,
+		scheduled.ContentType,
+		scheduled.MessageID,
+		scheduled.Priority,
+	); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	// queued -> scheduled is not a regular transition, so the status is restored directly
+	if _, err := tx.ExecContext(ctx, updateEmailStatusQuery, scheduled.EmailID, models.EmailStatusScheduled, "publish failed"); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	if _, err := tx.ExecContext(ctx, createStatusTransitionQuery, scheduled.EmailID, models.EmailStatusScheduled, "publish failed"); err != nil {
+		return errors.Wrap(err, "tx.ExecContext")
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
@@ -1,12 +1,81 @@
 package repository
 
 const (
-	createEmailQuery = `INSERT INTO emails ("to", "from", subject, body, content_type) VALUES ($1, $2, $3, $4, $5) RETURNING email_id`
+	createEmailQuery = `INSERT INTO emails 
+	("to", "from", subject, body, content_type, send_at, status, status_reason, cc, bcc, reply_to, headers, text_body, batch_id, category, priority, tenant_id, webhook_url) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING email_id, created_at, updated_at`
 
-	findEmailByIdQuery = `SELECT email_id, "to", "from", subject, body, content_type, created_at FROM emails WHERE email_id == $1`
+	emailByIdColumns = `email_id, "to", "from", subject, body, content_type, send_at, status, status_reason, created_at, updated_at, 
+	cc, reply_to, headers, text_body, category, provider, provider_message_id, priority, tenant_id, webhook_url`
 
-	totalCountQuery = `SELECT COUNT(email_id) AS totalCount FROM emails WHERE "to" ILIKE '%' || $1 || '%'`
+	findEmailByIdQuery = `SELECT ` + emailByIdColumns + ` FROM emails WHERE email_id = $1 AND tenant_id IS NOT DISTINCT FROM $2`
 
-	findEmailByReceiverQuery = `SELECT email_id, "to", "from", subject, body, content_type, created_at
-	FROM emails WHERE "to" ILIKE '%' || $1 || '%' ORDER BY created_at OFFSET $2 LIMIT $3`
+	findEmailByIdOfAnyTenantQuery = `SELECT ` + emailByIdColumns + ` FROM emails WHERE email_id = $1`
+
+	// bcc recipients are searchable, but never selected. Emails of the default tenant have no tenant id
+	receiverCondition = `tenant_id IS NOT DISTINCT FROM $2 
+	AND ("to" ILIKE '%' || $1 || '%' OR cc ILIKE '%' || $1 || '%' OR bcc ILIKE '%' || $1 || '%')`
+
+	totalCountQuery = `SELECT COUNT(email_id) AS totalCount FROM emails WHERE ` + receiverCondition
+
+	findEmailByReceiverQuery = `SELECT email_id, "to", "from", subject, body, content_type, send_at, status, status_reason, created_at, updated_at, 
+	cc, reply_to, headers, text_body, category, provider, provider_message_id, priority, tenant_id, webhook_url
+	FROM emails WHERE ` + receiverCondition + ` ORDER BY created_at OFFSET $3 LIMIT $4`
+
+	lockEmailStatusQuery = `SELECT status FROM emails WHERE email_id = $1 FOR UPDATE`
+
+	updateEmailStatusQuery = `UPDATE emails SET status = $2, status_reason = $3, updated_at = NOW() WHERE email_id = $1`
+
+	updateEmailProviderQuery = `UPDATE emails SET provider = $2, provider_message_id = $3, updated_at = NOW() WHERE email_id = $1`
+
+	createStatusTransitionQuery = `INSERT INTO email_status_transitions (email_id, status, reason) VALUES ($1, $2, $3)`
+
+	findStatusTransitionsQuery = `SELECT id, email_id, status, reason, created_at 
+	FROM email_status_transitions WHERE email_id = $1 ORDER BY created_at, id`
+
+	reserveIdempotencyKeyQuery = `INSERT INTO idempotency_keys (key, email_id, tenant_id) VALUES ($1, $2, $4)
+	ON CONFLICT (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'), key)
+	DO UPDATE SET email_id = EXCLUDED.email_id, created_at = NOW(), processed_at = NULL
+	WHERE idempotency_keys.created_at < $3 RETURNING email_id`
+
+	findIdempotencyKeyEmailQuery = `SELECT email_id FROM idempotency_keys WHERE key = $1 AND tenant_id IS NOT DISTINCT FROM $2`
+
+	isIdempotencyKeyProcessedQuery = `SELECT EXISTS(SELECT 1 FROM idempotency_keys 
+	WHERE key = $1 AND tenant_id IS NOT DISTINCT FROM $3 AND processed_at > $2)`
+
+	markIdempotencyKeyProcessedQuery = `UPDATE idempotency_keys SET processed_at = NOW() 
+	WHERE key = $1 AND tenant_id IS NOT DISTINCT FROM $2`
+
+	createScheduledEmailQuery = `INSERT INTO scheduled_emails (email_id, send_at, payload, content_type, message_id, priority) 
+	VALUES ($1, $2, $3, $4, $5, $6)`
+
+	claimDueScheduledEmailsQuery = `UPDATE scheduled_emails SET locked_until = $2
+	WHERE email_id IN (
+		SELECT email_id FROM scheduled_emails
+		WHERE send_at <= NOW() AND (locked_until IS NULL OR locked_until < NOW())
+		ORDER BY send_at LIMIT $1 FOR UPDATE SKIP LOCKED
+	) RETURNING email_id, send_at, payload, content_type, message_id, priority`
+
+	deleteScheduledEmailQuery = `DELETE FROM scheduled_emails WHERE email_id = $1`
+
+	createEmailBatchQuery = `INSERT INTO email_batches (total, tenant_id) VALUES ($1, $2) RETURNING batch_id, total, created_at`
+
+	findEmailBatchQuery = `SELECT batch_id, total, created_at FROM email_batches WHERE batch_id = $1 AND tenant_id IS NOT DISTINCT FROM $2`
+
+	// provider event delivered again is skipped, nothing is returned then
+	createEmailEventQuery = `INSERT INTO email_events (email_id, type, url, user_agent, ip, recipient, reason, provider_event_id) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')) 
+	ON CONFLICT (provider_event_id) WHERE provider_event_id IS NOT NULL DO NOTHING RETURNING event_id, created_at`
+
+	findEmailEventsQuery = `SELECT event_id, email_id, type, url, user_agent, ip, recipient, reason, 
+	COALESCE(provider_event_id, '') AS provider_event_id, created_at 
+	FROM email_events WHERE email_id = $1 ORDER BY created_at, event_id`
+
+	findEmailRecipientsQuery = `SELECT "to", cc, bcc FROM emails WHERE email_id = $1`
+
+	// message id is not unique across providers, the latest email wins
+	findEmailIdByMessageIdQuery = `SELECT email_id FROM emails WHERE provider_message_id = $1 
+	ORDER BY created_at DESC LIMIT 1`
+
+	countBatchEmailsByStatusQuery = `SELECT status, COUNT(email_id) FROM emails WHERE batch_id = $1 GROUP BY status`
 )
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"rmq_service/internal/email"
+	"rmq_service/pkg/logger"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+)
+
+var (
+	dispatchedEmails = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "emails_scheduled_dispatched_total",
+		Help: "The total number of scheduled emails published to the queue",
+	})
+)
+
+// Scheduled emails dispatcher
+type EmailsScheduler struct {
+	emailUC 	email.EmailsUseCase
+	logger 		logger.Logger
+	interval 	time.Duration
+}
+
+// Scheduled emails dispatcher constructor
+func NewEmailsScheduler(emailUC email.EmailsUseCase, logger logger.Logger, interval time.Duration) *EmailsScheduler {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &EmailsScheduler{emailUC: emailUC, logger: logger, interval: interval}
+}
+
+// Dispatch due emails until context is done
+func (s *EmailsScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Emails scheduler stopped")
+			return
+		case <-ticker.C:
+			s.dispatch(ctx)
+		}
+	}
+}
+
+// Dispatch all due emails batch by batch
+func (s *EmailsScheduler) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		dispatched, err := s.emailUC.DispatchScheduledEmails(ctx)
+		if err != nil {
+			s.logger.Errorf("emailUC.DispatchScheduledEmails: %v", err)
+			return
+		}
+
+		if dispatched == 0 {
+			return
+		}
+
+		dispatchedEmails.Add(float64(dispatched))
+		s.logger.Infof("Dispatched scheduled emails: %d", dispatched)
+	}
+}
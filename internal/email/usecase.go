@@ -12,8 +12,20 @@ import (
 
 // Email useCase interface
 type EmailsUseCase interface {
-	SendEmails(ctx context.Context, deliveryBody []byte) error
+	SendEmails(ctx context.Context, deliveryBody []byte, redelivered bool) error
+	NotifyEmailParked(ctx context.Context, deliveryBody []byte, cause error) error
+	RenderEmailTemplate(ctx context.Context, email *models.Email) error
 	PublishEmailToQueue(ctx context.Context, email *models.Email) error
+	SendBulkEmails(ctx context.Context, bulk *models.BulkEmail) (*models.EmailBatch, error)
+	GetEmailBatchProgress(ctx context.Context, batchID uuid.UUID) (*models.EmailBatchProgress, error)
+	DispatchScheduledEmails(ctx context.Context) (int, error)
 	FindEmailById(ctx context.Context, mailId uuid.UUID) (*models.Email, error)
 	FindEmailsByReceiver(ctx context.Context, mailTo string, query *utils.PaginationQuery) (*models.EmailsList, error)
+	RecordEmailEvent(ctx context.Context, event *models.EmailEvent) error
+	GetEmailEvents(ctx context.Context, emailID uuid.UUID) ([]*models.EmailEvent, error)
+	ApplyDeliveryEvent(ctx context.Context, event *models.DeliveryEvent) error
+	ListParkedEmails(ctx context.Context, filter *models.ParkedEmailsFilter) ([]*models.ParkedEmail, error)
+	GetParkedEmail(ctx context.Context, messageID string) (*models.ParkedEmail, error)
+	ReplayParkedEmails(ctx context.Context, filter *models.ParkedEmailsFilter) (int, error)
+	PurgeParkedEmails(ctx context.Context, filter *models.ParkedEmailsFilter, all bool) (int, error)
 }
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxBulkRecipients = 1000
+)
+
+// Render bulk email for every recipient and publish one message per recipient.
+// All recipients are rendered before the batch is created, so a single invalid recipient rejects the whole request.
+func (e *EmailUseCase) SendBulkEmails(ctx context.Context, bulk *models.BulkEmail) (*models.EmailBatch, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.SendBulkEmails")
+	defer span.Finish()
+
+	maxRecipients := e.cfg.Emails.MaxBulkRecipients
+	if maxRecipients <= 0 {
+		maxRecipients = defaultMaxBulkRecipients
+	}
+	if len(bulk.Recipients) > maxRecipients {
+		return nil, errors.Errorf("Validate recipients: max %d recipients allowed", maxRecipients)
+	}
+
+	if err := bulk.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	if err := e.validateWebhookURL(bulk.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	t, err := e.bulkTemplate(ctx, bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]*models.Email, 0, len(bulk.Recipients))
+	for _, recipient := range bulk.Recipients {
+		rendered, err := t.Render(recipient.Variables)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Template.Render %s", recipient.To)
+		}
+
+		mail := bulk.NewEmail(recipient, rendered)
+		mail.From = tenant.SenderFromContext(ctx, e.cfg.Smtp.User)
+		if err := mail.PrepareAndValidate(ctx); err != nil {
+			return nil, errors.Wrapf(err, "PrepareAndValidate %s", recipient.To)
+		}
+		emails = append(emails, mail)
+	}
+
+	// the whole batch is rejected when it does not fit into the quotas
+	sender := tenant.SenderFromContext(ctx, e.cfg.Smtp.User)
+	if err := e.quotasUC.Consume(ctx, sender, len(emails)); err != nil {
+		return nil, errors.Wrap(err, "quotasUC.Consume")
+	}
+
+	batch, err := e.emailsRepo.CreateEmailBatch(ctx, tenant.IDFromContext(ctx), len(emails))
+	if err != nil {
+		if err := e.quotasUC.Release(ctx, sender, len(emails)); err != nil {
+			e.logger.Errorf("quotasUC.Release %s: %v", sender, err)
+		}
+		return nil, errors.Wrap(err, "emailsRepo.CreateEmailBatch")
+	}
+
+	for _, mail := range emails {
+		mail.BatchID = &batch.BatchID
+		// failed email may have no stored row, so it is returned to the caller besides its status in the batch progress
+		if err := e.publishEmail(ctx, mail); err != nil {
+			e.logger.Errorf("publishEmail batch %v, to %s: %v", batch.BatchID, mail.GetToString(), err)
+			batch.Failed = append(batch.Failed, &models.BulkFailedRecipient{To: mail.GetToString(), Error: err.Error()})
+		}
+	}
+
+	span.LogFields(log.String("batchID", batch.BatchID.String()), log.Int("total", batch.Total), log.Int("failed", len(batch.Failed)))
+	return batch, nil
+}
+
+// Get emails batch progress
+func (e *EmailUseCase) GetEmailBatchProgress(ctx context.Context, batchID uuid.UUID) (*models.EmailBatchProgress, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.GetEmailBatchProgress")
+	defer span.Finish()
+
+	return e.emailsRepo.FindEmailBatchProgress(ctx, tenant.IDFromContext(ctx), batchID)
+}
+
+// Get stored template or the inline one from bulk email subject and body
+func (e *EmailUseCase) bulkTemplate(ctx context.Context, bulk *models.BulkEmail) (*models.Template, error) {
+	if bulk.TemplateID != nil {
+		t, err := e.templatesUC.GetTemplate(ctx, *bulk.TemplateID, bulk.TemplateVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "templatesUC.GetTemplate")
+		}
+		return t, nil
+	}
+
+	t := bulk.GetTemplate()
+	if err := t.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "Template.PrepareAndValidate")
+	}
+	return t, nil
+}
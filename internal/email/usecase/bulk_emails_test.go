@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rmq_service/internal/models"
+	"rmq_service/internal/quota"
+
+	"github.com/google/uuid"
+)
+
+// Quotas which always fit, released recipients are counted
+type fakeQuotas struct {
+	quota.QuotasUseCase
+	consumed int
+	released int
+}
+
+func (q *fakeQuotas) Consume(ctx context.Context, sender string, recipients int) error {
+	q.consumed += recipients
+	return nil
+}
+
+func (q *fakeQuotas) Release(ctx context.Context, sender string, recipients int) error {
+	q.released += recipients
+	return nil
+}
+
+// Repository of the bulk emails, every status change is recorded
+type fakeBulkEmailsRepo struct {
+	fakeEmailsRepo
+	created 	[]uuid.UUID
+	statuses map[uuid.UUID][]string
+}
+
+func (r *fakeBulkEmailsRepo) CreateEmailBatch(ctx context.Context, tenantID *uuid.UUID, total int) (*models.EmailBatch, error) {
+	return &models.EmailBatch{BatchID: uuid.New(), Total: total}, nil
+}
+
+func (r *fakeBulkEmailsRepo) CreateEmail(ctx context.Context, email *models.Email) (*models.Email, error) {
+	email.EmailID = uuid.New()
+	r.created = append(r.created, email.EmailID)
+	return email, nil
+}
+
+func (r *fakeBulkEmailsRepo) UpdateEmailStatus(ctx context.Context, id uuid.UUID, status, reason string, webhookDelivery *models.WebhookDelivery) error {
+	if r.statuses == nil {
+		r.statuses = make(map[uuid.UUID][]string)
+	}
+	r.statuses[id] = append(r.statuses[id], status)
+	return nil
+}
+
+func TestSendBulkEmailsReportsRecipientsFailedToPublish(t *testing.T) {
+	repo := &fakeBulkEmailsRepo{}
+	quotas := &fakeQuotas{}
+	uc := newTestEmailUseCase(repo, &fakePublisher{err: errors.New("channel closed")})
+	uc.quotasUC = quotas
+	uc.cfg.Smtp.User = "sender@example.com"
+
+	bulk := &models.BulkEmail{
+		Subject: 		"Hello {{.name}}",
+		Body: 			"Hi {{.name}}",
+		Recipients: []*models.BulkRecipient{
+			{To: "first@example.com", Variables: map[string]string{"name": "first"}},
+			{To: "second@example.com", Variables: map[string]string{"name": "second"}},
+		},
+	}
+
+	batch, err := uc.SendBulkEmails(context.Background(), bulk)
+	if err != nil {
+		t.Fatalf("SendBulkEmails: %v", err)
+	}
+	if batch.Total != 2 || quotas.consumed != 2 {
+		t.Fatalf("total %d, consumed %d, want 2", batch.Total, quotas.consumed)
+	}
+	if len(batch.Failed) != 2 {
+		t.Fatalf("failed %d recipients, want 2", len(batch.Failed))
+	}
+	for i, recipient := range bulk.Recipients {
+		if batch.Failed[i].To != recipient.To || batch.Failed[i].Error == "" {
+			t.Errorf("failed %+v, want %s with the error", batch.Failed[i], recipient.To)
+		}
+	}
+	for _, id := range repo.created {
+		statuses := repo.statuses[id]
+		if len(statuses) == 0 || statuses[len(statuses)-1] != models.EmailStatusFailed {
+			t.Errorf("email %v statuses %v, want failed last", id, statuses)
+		}
+	}
+}
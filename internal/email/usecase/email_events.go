@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+)
+
+// Record event of the email, opens are posted to the status webhook
+func (e *EmailUseCase) RecordEmailEvent(ctx context.Context, event *models.EmailEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.RecordEmailEvent")
+	defer span.Finish()
+
+	if _, err := e.emailsRepo.CreateEmailEvent(ctx, event); err != nil {
+		return errors.Wrap(err, "emailsRepo.CreateEmailEvent")
+	}
+
+	if event.Type == models.EmailEventOpen {
+		e.enqueueWebhookEvent(ctx, &models.WebhookEvent{
+			Type: 			models.WebhookEventOpened,
+			EmailID: 		event.EmailID,
+			CreatedAt: 	event.CreatedAt,
+		})
+	}
+
+	span.LogFields(log.String("emailID", event.EmailID.String()), log.String("type", event.Type))
+	return nil
+}
+
+// Get events of the request tenant email
+func (e *EmailUseCase) GetEmailEvents(ctx context.Context, emailID uuid.UUID) ([]*models.EmailEvent, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.GetEmailEvents")
+	defer span.Finish()
+
+	// email of another tenant is not found
+	if _, err := e.emailsRepo.FindEmailById(ctx, tenant.IDFromContext(ctx), emailID); err != nil {
+		return nil, errors.Wrap(err, "emailsRepo.FindEmailById")
+	}
+
+	return e.emailsRepo.FindEmailEvents(ctx, emailID)
+}
+
+// Apply delivery event reported by the provider or the bounce report: delivery and hard bounce change
+// the email status, hard bounced and complaining recipients are suppressed and the event is recorded.
+// Event is recorded last, so the event failed half way is applied again when the provider retries it,
+// and the provider event delivered again changes nothing. Event without email id is matched to the email
+// by the provider message id. Event in the context of the tenant, e.g. verified by the tenant webhook key,
+// applies to the emails of the tenant only and suppresses the recipient for the tenant,
+// other events suppress the recipient globally.
+func (e *EmailUseCase) ApplyDeliveryEvent(ctx context.Context, event *models.DeliveryEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.ApplyDeliveryEvent")
+	defer span.Finish()
+
+	if event.EmailID == uuid.Nil {
+		if err := e.matchDeliveryEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	mail, err := e.findEventEmail(ctx, event.EmailID)
+	if err != nil {
+		return err
+	}
+
+	if status := event.GetEmailStatus(); status != "" {
+		err := e.setRecipientEmailStatus(ctx, mail, status, event.Recipient, event.Reason)
+		// late events are only recorded, e.g. delivery of another recipient of the bounced email
+		if errors.Is(err, grpc_errors.ErrInvalidEmailStatus) {
+			e.logger.Warnf("Keep status of email %v on %s event: %v", event.EmailID, event.Type, err)
+		} else if err != nil {
+			return errors.Wrap(err, "setRecipientEmailStatus")
+		}
+	}
+
+	if reason := event.GetSuppressionReason(); reason != "" {
+		suppression := &models.Suppression{Address: event.Recipient, Reason: reason}
+		if _, ok := tenant.FromContext(ctx); ok {
+			suppression.TenantID = mail.TenantID
+		}
+		if _, err := e.suppressionsUC.AddSuppression(ctx, suppression); err != nil {
+			return errors.Wrap(err, "suppressionsUC.AddSuppression")
+		}
+		e.logger.Infof("Recipient %s of email %v suppressed: %s", event.Recipient, event.EmailID, reason)
+	}
+
+	created, err := e.emailsRepo.CreateEmailEvent(ctx, &models.EmailEvent{
+		EmailID: 					event.EmailID,
+		Type: 						event.Type,
+		Recipient: 				event.Recipient,
+		Reason: 					event.Reason,
+		ProviderEventID: 	event.ProviderEventID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "emailsRepo.CreateEmailEvent")
+	}
+
+	span.LogFields(log.String("emailID", event.EmailID.String()), log.String("type", event.Type), log.Bool("created", created))
+	return nil
+}
+
+// Find email of the delivery event, email of another tenant is not matched for the event of the tenant
+func (e *EmailUseCase) findEventEmail(ctx context.Context, emailID uuid.UUID) (*models.Email, error) {
+	var mail *models.Email
+	var err error
+	if tenantID := tenant.IDFromContext(ctx); tenantID != nil {
+		mail, err = e.emailsRepo.FindEmailById(ctx, tenantID, emailID)
+	} else {
+		mail, err = e.emailsRepo.FindEmailByIdOfAnyTenant(ctx, emailID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(grpc_errors.ErrEmailNotMatched, "email id %v", emailID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "emailsRepo.FindEmailById")
+	}
+	return mail, nil
+}
+
+// Set id of the email sent with the provider message id of the event
+func (e *EmailUseCase) matchDeliveryEvent(ctx context.Context, event *models.DeliveryEvent) error {
+	if event.MessageID == "" {
+		return errors.Wrap(grpc_errors.ErrEmailNotMatched, "no message id")
+	}
+
+	id, err := e.emailsRepo.FindEmailIdByMessageID(ctx, event.MessageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrapf(grpc_errors.ErrEmailNotMatched, "message id %q", event.MessageID)
+	}
+	if err != nil {
+		return errors.Wrap(err, "emailsRepo.FindEmailIdByMessageID")
+	}
+
+	event.EmailID = id
+	return nil
+}
+
+// Rewrite links and add open pixel to html body of the email which opted in to tracking
+func (e *EmailUseCase) trackEmail(email *models.Email) error {
+	if !email.Track || !email.IsHTML() || !e.trackingTokens.Enabled() {
+		return nil
+	}
+
+	body, err := e.trackingTokens.TrackHTML(email.EmailID, email.Body)
+	if err != nil {
+		return errors.Wrap(err, "trackingTokens.TrackHTML")
+	}
+
+	email.Body = body
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"rmq_service/internal/models"
+	"rmq_service/internal/suppression"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/google/uuid"
+)
+
+// Repository of the emails with delivery events, emails are found by id and tenant
+type fakeEventsRepo struct {
+	fakeBulkEmailsRepo
+	emails map[uuid.UUID]*models.Email
+	events []*models.EmailEvent
+}
+
+func (r *fakeEventsRepo) FindEmailById(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*models.Email, error) {
+	mail, ok := r.emails[id]
+	if !ok || (tenantID == nil) != (mail.TenantID == nil) || (tenantID != nil && *tenantID != *mail.TenantID) {
+		return nil, sql.ErrNoRows
+	}
+	return mail, nil
+}
+
+func (r *fakeEventsRepo) FindEmailByIdOfAnyTenant(ctx context.Context, id uuid.UUID) (*models.Email, error) {
+	mail, ok := r.emails[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return mail, nil
+}
+
+func (r *fakeEventsRepo) CreateEmailEvent(ctx context.Context, event *models.EmailEvent) (bool, error) {
+	r.events = append(r.events, event)
+	return true, nil
+}
+
+// Suppressions recording added ones
+type fakeSuppressions struct {
+	suppression.SuppressionsUseCase
+	added []*models.Suppression
+}
+
+func (s *fakeSuppressions) AddSuppression(ctx context.Context, suppression *models.Suppression) (*models.Suppression, error) {
+	s.added = append(s.added, suppression)
+	return suppression, nil
+}
+
+func newTestEventsUseCase(mail *models.Email) (*EmailUseCase, *fakeEventsRepo, *fakeSuppressions) {
+	repo := &fakeEventsRepo{emails: map[uuid.UUID]*models.Email{mail.EmailID: mail}}
+	suppressions := &fakeSuppressions{}
+	uc := newTestEmailUseCase(repo, &fakePublisher{})
+	uc.suppressionsUC = suppressions
+	return uc, repo, suppressions
+}
+
+func TestApplyDeliveryEventOfTenantSkipsOtherTenantEmail(t *testing.T) {
+	owner := uuid.New()
+	mail := &models.Email{EmailID: uuid.New(), TenantID: &owner, Status: models.EmailStatusSent}
+	uc, repo, suppressions := newTestEventsUseCase(mail)
+
+	ctx := tenant.WithTenant(context.Background(), &models.Tenant{TenantID: uuid.New()})
+	err := uc.ApplyDeliveryEvent(ctx, &models.DeliveryEvent{EmailID: mail.EmailID, Type: models.EmailEventBounce, Recipient: "to@example.com"})
+
+	if !errors.Is(err, grpc_errors.ErrEmailNotMatched) {
+		t.Fatalf("ApplyDeliveryEvent error %v, want email not matched", err)
+	}
+	if len(repo.statuses) != 0 || len(suppressions.added) != 0 || len(repo.events) != 0 {
+		t.Fatalf("statuses %v, suppressions %d, events %d, want nothing applied", repo.statuses, len(suppressions.added), len(repo.events))
+	}
+}
+
+func TestApplyDeliveryEventSuppressionScope(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name 	string
+		ctx 	context.Context
+		want 	*uuid.UUID
+	}{
+		{"tenant webhook", tenant.WithTenant(context.Background(), &models.Tenant{TenantID: owner}), &owner},
+		{"deployment webhook", context.Background(), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mail := &models.Email{EmailID: uuid.New(), TenantID: &owner, Status: models.EmailStatusSent}
+			uc, repo, suppressions := newTestEventsUseCase(mail)
+
+			event := &models.DeliveryEvent{EmailID: mail.EmailID, Type: models.EmailEventComplaint, Recipient: "to@example.com"}
+			if err := uc.ApplyDeliveryEvent(tt.ctx, event); err != nil {
+				t.Fatalf("ApplyDeliveryEvent: %v", err)
+			}
+
+			if len(suppressions.added) != 1 || len(repo.events) != 1 {
+				t.Fatalf("suppressions %d, events %d, want one of each", len(suppressions.added), len(repo.events))
+			}
+			got := suppressions.added[0].TenantID
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("suppression tenant %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
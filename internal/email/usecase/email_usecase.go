@@ -3,11 +3,24 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
 	"rmq_service/config"
 	"rmq_service/internal/email"
+	"rmq_service/internal/email/ratelimit"
 	"rmq_service/internal/models"
+	"rmq_service/internal/quota"
+	"rmq_service/internal/suppression"
+	"rmq_service/internal/template"
+	"rmq_service/internal/tenant"
+	"rmq_service/internal/tracking"
+	"rmq_service/internal/webhook"
+	"rmq_service/pkg/grpc_errors"
 	"rmq_service/pkg/logger"
+	"rmq_service/pkg/mime_types"
 	"rmq_service/pkg/utils"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
@@ -15,6 +28,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	defaultSchedulerBatchSize = 100
+	defaultSchedulerLease 		= time.Minute
+)
+
 // Email usecase struct
 type EmailUseCase struct {
 	mailer 				email.Mailer
@@ -22,6 +40,13 @@ type EmailUseCase struct {
 	logger 				logger.Logger
 	cfg 					*config.Config
 	publisher 		email.EmailsPublisher
+	templatesUC 	template.TemplatesUseCase
+	attachmentsRepo email.AttachmentsAWSRepository
+	suppressionsUC 	suppression.SuppressionsUseCase
+	tenantsUC 			tenant.TenantsUseCase
+	quotasUC 				quota.QuotasUseCase
+	trackingTokens 	*tracking.Tokens
+	webhooksUC 			webhook.WebhooksUseCase
 }
 
 // EmailUseCase constructor
@@ -30,12 +55,32 @@ func NewEmailUseCase(
 	emailsRepo email.EmailsRepository,
 	logger logger.Logger,
 	cfg *config.Config,
-	publisher email.EmailsPublisher) *EmailUseCase {
-		return &EmailUseCase{ mailer: mailer,emailsRepo: emailsRepo,logger: logger,cfg: cfg,publisher: publisher }
+	publisher email.EmailsPublisher,
+	templatesUC template.TemplatesUseCase,
+	attachmentsRepo email.AttachmentsAWSRepository,
+	suppressionsUC suppression.SuppressionsUseCase,
+	tenantsUC tenant.TenantsUseCase,
+	quotasUC quota.QuotasUseCase,
+	trackingTokens *tracking.Tokens,
+	webhooksUC webhook.WebhooksUseCase) *EmailUseCase {
+		return &EmailUseCase{
+			mailer: mailer,
+			emailsRepo: emailsRepo,
+			logger: logger,
+			cfg: cfg,
+			publisher: publisher,
+			templatesUC: templatesUC,
+			attachmentsRepo: attachmentsRepo,
+			suppressionsUC: suppressionsUC,
+			tenantsUC: tenantsUC,
+			quotasUC: quotasUC,
+			trackingTokens: trackingTokens,
+			webhooksUC: webhooksUC,
+		}
 }
 
-// Send Email
-func (e *EmailUseCase) SendEmails(ctx context.Context, deliveryBody []byte) error {
+// Send Email, redelivered message of the email left in sending status is sent again
+func (e *EmailUseCase) SendEmails(ctx context.Context, deliveryBody []byte, redelivered bool) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.SendEmails")
 	defer span.Finish()
 
@@ -44,48 +89,511 @@ func (e *EmailUseCase) SendEmails(ctx context.Context, deliveryBody []byte) erro
 		return errors.Wrap(err, "json.Unmarshal")
 	}
 
-	mail.Body = utils.SanitizeString(mail.Body)
-	mail.From = e.cfg.Smtp.User
+	// messages published before statuses were tracked have no stored row yet
+	if mail.EmailID == uuid.Nil {
+		mail.Status = models.EmailStatusQueued
+		if _, err := e.emailsRepo.CreateEmail(ctx, mail); err != nil {
+			return errors.Wrap(err, "emailsRepo.CreateEmail")
+		}
+	}
+
+	if mail.IdempotencyKey != "" {
+		processed, err := e.emailsRepo.IsIdempotencyKeyProcessed(ctx, mail.TenantID, mail.IdempotencyKey)
+		if err != nil {
+			return errors.Wrap(err, "emailsRepo.IsIdempotencyKeyProcessed")
+		}
+		if processed {
+			e.logger.Warnf("Skip duplicate email %v, idempotency key: %s", mail.EmailID, mail.IdempotencyKey)
+			return nil
+		}
+	}
+
+	if err := e.startSending(ctx, mail, redelivered); err != nil {
+		if errors.Is(err, grpc_errors.ErrInvalidEmailStatus) {
+			e.logger.Warnf("Skip email %v: %v", mail.EmailID, err)
+			return nil
+		}
+		return errors.Wrap(err, "startSending")
+	}
+
+	if mail.IsHTML() {
+		mail.Body = utils.SanitizeString(mail.Body)
+	}
+
+	// tracking markup is added after sanitizing, so the sanitizer does not strip it
+	if err := e.trackEmail(mail); err != nil {
+		return e.failEmail(ctx, mail, errors.Wrap(err, "trackEmail"))
+	}
+
+	ctx, err := e.tenantContext(ctx, mail)
+	if err != nil {
+		return e.failEmail(ctx, mail, errors.Wrap(err, "tenantContext"))
+	}
+	mail.From = tenant.SenderFromContext(ctx, e.cfg.Smtp.User)
 
 	if err := utils.ValidateStruct(ctx, mail); err != nil {
-		return errors.Wrap(err, "ValidateStruct")
+		return e.failEmail(ctx, mail, errors.Wrap(err, "ValidateStruct"))
+	}
+
+	dropped, err := e.dropSuppressed(ctx, mail)
+	if err != nil {
+		return e.failEmail(ctx, mail, errors.Wrap(err, "dropSuppressed"))
+	}
+
+	reason := strings.Join(dropped, "; ")
+	if len(mail.GetRecipients()) == 0 {
+		e.markIdempotencyKeyProcessed(ctx, mail)
+		if err := e.setEmailStatus(ctx, mail, models.EmailStatusSuppressed, reason); err != nil {
+			return errors.Wrap(err, "setEmailStatus")
+		}
+		e.logger.Infof("All recipients of email %v are suppressed", mail.EmailID)
+		return nil
+	}
+
+	if err := e.loadAttachments(ctx, mail); err != nil {
+		return e.failEmail(ctx, mail, errors.Wrap(err, "loadAttachments"))
 	}
 
 	if err := e.mailer.Send(ctx, mail); err != nil {
-		return errors.Wrap(err, "mailer.Send")
+		var limitErr *ratelimit.LimitExceededError
+		if errors.As(err, &limitErr) {
+			return e.requeueLimited(ctx, mail, limitErr)
+		}
+		return e.failEmail(ctx, mail, errors.Wrap(err, "mailer.Send"))
+	}
+
+	e.markIdempotencyKeyProcessed(ctx, mail)
+
+	if err := e.emailsRepo.UpdateEmailProvider(ctx, mail.EmailID, mail.Provider, mail.ProviderMessageID); err != nil {
+		e.logger.Errorf("emailsRepo.UpdateEmailProvider %v: %v", mail.EmailID, err)
+	}
+
+	// dropped recipients are recorded as the reason of sent status
+	if err := e.setEmailStatus(ctx, mail, models.EmailStatusSent, reason); err != nil {
+		return errors.Wrap(err, "setEmailStatus")
 	}
 
-	createdEmail, err := e.emailsRepo.CreateEmail(ctx, mail)
+	span.LogFields(log.String("emailID", mail.EmailID.String()), log.String("provider", mail.Provider))
+	e.logger.Infof("Success sent email: %v, provider: %s", mail.EmailID, mail.Provider)
+	return nil
+}
+
+// Render email subject and body from the stored template
+func (e *EmailUseCase) RenderEmailTemplate(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.RenderEmailTemplate")
+	defer span.Finish()
+
+	if email.TemplateID == nil {
+		return nil
+	}
+
+	rendered, err := e.templatesUC.Render(ctx, *email.TemplateID, email.TemplateVersion, email.Variables)
 	if err != nil {
-		return errors.Wrap(err, "emailRepo.CreateEmail")
+		return errors.Wrap(err, "templatesUC.Render")
 	}
 
-	span.LogFields(log.String("emailID", createdEmail.EmailID.String()))
-	e.logger.Infof("Success sent email: %v", createdEmail.EmailID)
+	email.SetRenderedTemplate(rendered)
+
+	span.LogFields(log.String("templateID", email.TemplateID.String()))
 	return nil
 }
 
+// Store accepted email and publish it to the queue, recipients are taken from the quotas.
+// Repeated email with the same idempotency key gets the original email id and is not published again.
 func (e *EmailUseCase) PublishEmailToQueue(ctx context.Context, email *models.Email) error {
-	span, _ := opentracing.StartSpanFromContext(ctx, "EmailUseCase.PublishEmailToQueue")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.PublishEmailToQueue")
 	defer span.Finish()
 
+	if err := e.validateAttachments(ctx, email); err != nil {
+		return errors.Wrap(err, "validateAttachments")
+	}
+
+	if err := e.validateWebhookURL(email.WebhookURL); err != nil {
+		return err
+	}
+
+	if err := e.quotasUC.Consume(ctx, email.From, len(email.GetRecipients())); err != nil {
+		return errors.Wrap(err, "quotasUC.Consume")
+	}
+
+	return e.publishEmail(ctx, email)
+}
+
+// Store email and publish it to the queue, recipients must be already taken from the quotas
+func (e *EmailUseCase) publishEmail(ctx context.Context, email *models.Email) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.publishEmail")
+	defer span.Finish()
+
+	email.Status = models.EmailStatusAccepted
+	email.TenantID = tenant.IDFromContext(ctx)
+	if _, err := e.emailsRepo.CreateEmail(ctx, email); err != nil {
+		e.releaseQuotas(ctx, email)
+		if errors.Is(err, grpc_errors.ErrEmailExists) {
+			e.logger.Infof("Duplicate email %v, idempotency key: %s", email.EmailID, email.IdempotencyKey)
+			return nil
+		}
+		return errors.Wrap(err, "emailsRepo.CreateEmail")
+	}
+
+	if err := e.offloadAttachments(ctx, email); err != nil {
+		return e.failEmail(ctx, email, errors.Wrap(err, "offloadAttachments"))
+	}
+
 	mailBytes, err := json.Marshal(email)
 	if err != nil {
-		return errors.Wrap(err, "json.Marshall")
+		return e.failEmail(ctx, email, errors.Wrap(err, "json.Marshall"))
+	}
+
+	if email.IsScheduled() {
+		if err := e.emailsRepo.CreateScheduledEmail(ctx, &models.ScheduledEmail{
+			EmailID: 			email.EmailID,
+			SendAt: 			*email.SendAt,
+			Payload: 			mailBytes,
+			ContentType: 	mime_types.MIMEApplicationJSON,
+			MessageID: 		email.IdempotencyKey,
+			Priority: 		email.Priority,
+		}); err != nil {
+			return e.failEmail(ctx, email, errors.Wrap(err, "emailsRepo.CreateScheduledEmail"))
+		}
+
+		email.Status = models.EmailStatusScheduled
+		span.LogFields(log.String("emailID", email.EmailID.String()), log.String("sendAt", email.SendAt.String()))
+		return nil
+	}
+
+	if err := e.setEmailStatus(ctx, email, models.EmailStatusQueued, ""); err != nil {
+		return errors.Wrap(err, "setEmailStatus")
+	}
+
+	if err := e.publisher.Publish(mailBytes, mime_types.MIMEApplicationJSON, email.IdempotencyKey, models.GetEmailQueuePriority(email.Priority)); err != nil {
+		return e.failEmail(ctx, email, errors.Wrap(err, "publisher.Publish"))
+	}
+
+	span.LogFields(log.String("emailID", email.EmailID.String()))
+	return nil
+}
+
+// Publish scheduled emails which send time has come, returns number of dispatched emails
+func (e *EmailUseCase) DispatchScheduledEmails(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.DispatchScheduledEmails")
+	defer span.Finish()
+
+	batchSize := e.cfg.Emails.SchedulerBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSchedulerBatchSize
+	}
+
+	lease := e.cfg.Emails.SchedulerLease
+	if lease <= 0 {
+		lease = defaultSchedulerLease
+	}
+
+	scheduled, err := e.emailsRepo.ClaimDueScheduledEmails(ctx, batchSize, lease)
+	if err != nil {
+		return 0, errors.Wrap(err, "emailsRepo.ClaimDueScheduledEmails")
+	}
+
+	dispatched := 0
+	for _, s := range scheduled {
+		// email is queued before publishing, so the consumer never sees it still scheduled,
+		// not completed emails are claimed again after the lease ends
+		if err := e.emailsRepo.CompleteScheduledEmail(ctx, s.EmailID); err != nil {
+			e.logger.Errorf("emailsRepo.CompleteScheduledEmail %v: %v", s.EmailID, err)
+			continue
+		}
+
+		if err := e.publisher.Publish(s.Payload, s.ContentType, s.MessageID, models.GetEmailQueuePriority(s.Priority)); err != nil {
+			e.logger.Errorf("publisher.Publish scheduled email %v: %v", s.EmailID, err)
+			if err := e.emailsRepo.RescheduleEmail(ctx, s); err != nil {
+				e.logger.Errorf("emailsRepo.RescheduleEmail %v: %v", s.EmailID, err)
+			}
+			continue
+		}
+		dispatched++
+	}
+
+	span.LogFields(log.Int("dispatched", dispatched))
+	return dispatched, nil
+}
+
+// Check attachments size limits, sizes of referenced blobs are read from the storage.
+// Only blobs of the request tenant can be referenced.
+func (e *EmailUseCase) validateAttachments(ctx context.Context, email *models.Email) error {
+	tenantID := tenant.IDFromContext(ctx)
+	for _, a := range email.Attachments {
+		if len(a.Content) > 0 || a.BlobKey == "" {
+			continue
+		}
+
+		if !models.IsTenantBlobKey(tenantID, a.BlobKey) {
+			return errors.Wrapf(grpc_errors.ErrInvalidBlobKey, "%s", a.BlobKey)
+		}
+
+		size, err := e.attachmentsRepo.GetAttachmentSize(ctx, a.BlobKey)
+		if err != nil {
+			return errors.Wrap(err, "attachmentsRepo.GetAttachmentSize")
+		}
+		a.Size = size
+	}
+
+	return models.ValidateAttachments(email.Attachments, e.cfg.Emails.MaxAttachmentSize, e.cfg.Emails.MaxAttachmentsSize)
+}
+
+// Move large attachments to the blob storage, so they are not carried in the queue message
+func (e *EmailUseCase) offloadAttachments(ctx context.Context, email *models.Email) error {
+	threshold := e.cfg.Emails.AttachmentBlobThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	for i, a := range email.Attachments {
+		if int64(len(a.Content)) <= threshold {
+			continue
+		}
+
+		key := fmt.Sprintf("%s%d-%s", models.AttachmentsEmailPrefix(email.TenantID, email.EmailID), i, path.Base(a.Filename))
+		if err := e.attachmentsRepo.PutAttachment(ctx, key, a.ContentType, a.Content); err != nil {
+			return errors.Wrap(err, "attachmentsRepo.PutAttachment")
+		}
+
+		a.BlobKey = key
+		a.Content = nil
+	}
+
+	return nil
+}
+
+// Download attachments stored in the blob storage, blobs of other tenants are refused
+// in case the message was published to the queue directly
+func (e *EmailUseCase) loadAttachments(ctx context.Context, email *models.Email) error {
+	for _, a := range email.Attachments {
+		if len(a.Content) > 0 || a.BlobKey == "" {
+			continue
+		}
+
+		if !models.IsTenantBlobKey(email.TenantID, a.BlobKey) {
+			return errors.Wrapf(grpc_errors.ErrInvalidBlobKey, "%s", a.BlobKey)
+		}
+
+		content, err := e.attachmentsRepo.GetAttachment(ctx, a.BlobKey)
+		if err != nil {
+			return errors.Wrap(err, "attachmentsRepo.GetAttachment")
+		}
+		a.Content = content
+	}
+
+	return nil
+}
+
+// Remove attachments offloaded for the email once it is not going to be sent anymore,
+// failures are only logged and blobs referenced by the client are kept
+func (e *EmailUseCase) removeAttachments(ctx context.Context, email *models.Email) {
+	prefix := models.AttachmentsEmailPrefix(email.TenantID, email.EmailID)
+
+	offloaded := false
+	for _, a := range email.Attachments {
+		if strings.HasPrefix(a.BlobKey, prefix) {
+			offloaded = true
+			break
+		}
+	}
+	if !offloaded {
+		return
+	}
+
+	if err := e.attachmentsRepo.DeleteAttachments(ctx, prefix); err != nil {
+		e.logger.Errorf("attachmentsRepo.DeleteAttachments %v: %v", email.EmailID, err)
+	}
+}
+
+// Remove suppressed recipients from email
+func (e *EmailUseCase) dropSuppressed(ctx context.Context, email *models.Email) ([]string, error) {
+	suppressed, err := e.suppressionsUC.FindSuppressed(ctx, email.GetRecipients(), email.Category)
+	if err != nil {
+		return nil, errors.Wrap(err, "suppressionsUC.FindSuppressed")
+	}
+
+	if len(suppressed) == 0 {
+		return nil, nil
+	}
+
+	dropped := email.DropSuppressed(suppressed)
+	for _, d := range dropped {
+		e.logger.Warnf("Email %v: %s", email.EmailID, d)
+	}
+	return dropped, nil
+}
+
+// Context of the email tenant, emails of disabled tenants are not sent
+func (e *EmailUseCase) tenantContext(ctx context.Context, email *models.Email) (context.Context, error) {
+	if email.TenantID == nil {
+		return ctx, nil
+	}
+
+	t, err := e.tenantsUC.GetTenant(ctx, *email.TenantID)
+	if err != nil {
+		return ctx, errors.Wrap(err, "tenantsUC.GetTenant")
 	}
 
-	return e.publisher.Publish(mailBytes, email.ContentType)
+	if t.Disabled {
+		return ctx, grpc_errors.ErrTenantDisabled
+	}
+
+	return tenant.WithTenant(ctx, t), nil
+}
+
+// Return email rate limited by every provider to the queue status, the consumer delays its message
+func (e *EmailUseCase) requeueLimited(ctx context.Context, email *models.Email, limitErr *ratelimit.LimitExceededError) error {
+	if err := e.setEmailStatus(ctx, email, models.EmailStatusQueued, limitErr.Error()); err != nil {
+		return errors.Wrap(err, "setEmailStatus")
+	}
+	return limitErr
+}
+
+// Return recipients of the not stored email to the quotas
+func (e *EmailUseCase) releaseQuotas(ctx context.Context, email *models.Email) {
+	if err := e.quotasUC.Release(ctx, email.From, len(email.GetRecipients())); err != nil {
+		e.logger.Errorf("quotasUC.Release %s: %v", email.From, err)
+	}
+}
+
+// Mark idempotency key of the processed email, so duplicates are skipped
+func (e *EmailUseCase) markIdempotencyKeyProcessed(ctx context.Context, email *models.Email) {
+	if email.IdempotencyKey == "" {
+		return
+	}
+
+	if err := e.emailsRepo.MarkIdempotencyKeyProcessed(ctx, email.TenantID, email.IdempotencyKey); err != nil {
+		e.logger.Errorf("emailsRepo.MarkIdempotencyKeyProcessed: %v", err)
+	}
 }
 
-// Find email by uuid
+// Move email to sending status. Message redelivered by the broker after an interrupted send
+// finds the email still sending, the send is resumed then instead of skipping the email for good.
+func (e *EmailUseCase) startSending(ctx context.Context, email *models.Email, redelivered bool) error {
+	err := e.setEmailStatus(ctx, email, models.EmailStatusSending, "")
+	if err == nil || !redelivered || !errors.Is(err, grpc_errors.ErrInvalidEmailStatus) {
+		return err
+	}
+
+	resumed, resumeErr := e.emailsRepo.ResumeEmailSending(ctx, email.EmailID)
+	if resumeErr != nil {
+		return errors.Wrap(resumeErr, "emailsRepo.ResumeEmailSending")
+	}
+	if !resumed {
+		return err
+	}
+
+	e.logger.Warnf("Resume sending of redelivered email %v", email.EmailID)
+	email.Status = models.EmailStatusSending
+	return nil
+}
+
+// Change email status and record the transition. Every status is published as the lifecycle event,
+// sent and bounced statuses are posted to the status webhook as well, its outbox delivery is added with the status.
+func (e *EmailUseCase) setEmailStatus(ctx context.Context, email *models.Email, status, reason string) error {
+	return e.setRecipientEmailStatus(ctx, email, status, "", reason)
+}
+
+// Change email status on the delivery event of the recipient, e.g. the bounce, the recipient is posted
+// to the status webhook and prefixes the reason of the status
+func (e *EmailUseCase) setRecipientEmailStatus(ctx context.Context, email *models.Email, status, recipient, reason string) error {
+	var webhookDelivery *models.WebhookDelivery
+	if eventType := models.GetWebhookEventType(status); eventType != "" {
+		delivery, err := models.NewWebhookDelivery(&models.WebhookEvent{
+			Type: 			eventType,
+			EmailID: 		email.EmailID,
+			Status: 		status,
+			Recipient: 	recipient,
+			Reason: 		reason,
+		})
+		if err != nil {
+			return errors.Wrap(err, "models.NewWebhookDelivery")
+		}
+		webhookDelivery = delivery
+	}
+
+	if recipient != "" {
+		reason = recipient + ": " + reason
+	}
+
+	if err := e.emailsRepo.UpdateEmailStatus(ctx, email.EmailID, status, reason, webhookDelivery); err != nil {
+		return err
+	}
+
+	email.Status = status
+	email.StatusReason = reason
+
+	if status == models.EmailStatusSent || models.IsFinalEmailStatus(status) {
+		e.removeAttachments(ctx, email)
+	}
+
+	// failures are only logged, the status is already changed
+	if err := e.publisher.PublishEvent(models.NewLifecycleEvent(email)); err != nil {
+		e.logger.Errorf("publisher.PublishEvent %v: %v", email.EmailID, err)
+	}
+	return nil
+}
+
+// Post email.failed to the status webhook of the email parked after its last attempt or a permanent failure.
+// Failed attempts which are retried are not posted.
+func (e *EmailUseCase) NotifyEmailParked(ctx context.Context, deliveryBody []byte, cause error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailUseCase.NotifyEmailParked")
+	defer span.Finish()
+
+	mail := &models.Email{}
+	if err := json.Unmarshal(deliveryBody, mail); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+	if mail.EmailID == uuid.Nil {
+		return nil
+	}
+
+	if err := e.webhooksUC.EnqueueEvent(ctx, &models.WebhookEvent{
+		Type: 		models.WebhookEventFailed,
+		EmailID: 	mail.EmailID,
+		Status: 	models.EmailStatusFailed,
+		Reason: 	cause.Error(),
+	}); err != nil {
+		return errors.Wrap(err, "webhooksUC.EnqueueEvent")
+	}
+
+	span.LogFields(log.String("emailID", mail.EmailID.String()))
+	return nil
+}
+
+// Check status webhook URL given by the caller, empty URL falls back to the tenant one
+func (e *EmailUseCase) validateWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	return errors.Wrap(webhook.ValidateURL(webhookURL, webhook.RequireHTTPS(e.cfg)), "webhook.ValidateURL")
+}
+
+// Add event to the status webhook outbox, failures are only logged so the email is never affected
+func (e *EmailUseCase) enqueueWebhookEvent(ctx context.Context, event *models.WebhookEvent) {
+	if err := e.webhooksUC.EnqueueEvent(ctx, event); err != nil {
+		e.logger.Errorf("webhooksUC.EnqueueEvent %v: %v", event.EmailID, err)
+	}
+}
+
+// Mark email as failed and return the cause
+func (e *EmailUseCase) failEmail(ctx context.Context, email *models.Email, cause error) error {
+	if err := e.setEmailStatus(ctx, email, models.EmailStatusFailed, cause.Error()); err != nil {
+		e.logger.Errorf("setEmailStatus %v failed: %v", email.EmailID, err)
+	}
+	return cause
+}
+
+// Find email of the request tenant by uuid
 func (e *EmailUseCase) FindEmailById(ctx context.Context, emailID uuid.UUID) (*models.Email, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.FindEmailById")
 	defer span.Finish()
 
-	return e.emailsRepo.FindEmailById(ctx, emailID)
+	return e.emailsRepo.FindEmailById(ctx, tenant.IDFromContext(ctx), emailID)
 }
 
-// Find emails by receiver
+// Find emails of the request tenant by receiver
 func (e *EmailUseCase) FindEmailsByReceiver(
 		ctx context.Context,
 		mailTo string,
@@ -94,5 +602,72 @@ func (e *EmailUseCase) FindEmailsByReceiver(
 	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.FindEmailsByReceiver")
 	defer span.Finish()
 
-	return e.emailsRepo.FindEmailsByReceiver(ctx, mailTo, query)
+	return e.emailsRepo.FindEmailsByReceiver(ctx, tenant.IDFromContext(ctx), mailTo, query)
+}
+
+// List parked emails
+func (e *EmailUseCase) ListParkedEmails(
+	ctx context.Context,
+	filter *models.ParkedEmailsFilter,
+) ([]*models.ParkedEmail, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.ListParkedEmails")
+	defer span.Finish()
+
+	return e.publisher.ListParked(ctx, filter)
+}
+
+// Get parked email by message id
+func (e *EmailUseCase) GetParkedEmail(ctx context.Context, messageID string) (*models.ParkedEmail, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.GetParkedEmail")
+	defer span.Finish()
+
+	parked, err := e.publisher.ListParked(ctx, &models.ParkedEmailsFilter{MessageID: messageID, Limit: 1})
+	if err != nil {
+		return nil, errors.Wrap(err, "publisher.ListParked")
+	}
+
+	if len(parked) == 0 {
+		return nil, grpc_errors.ErrNotFound
+	}
+
+	return parked[0], nil
+}
+
+// Replay parked emails to the main queue
+func (e *EmailUseCase) ReplayParkedEmails(ctx context.Context, filter *models.ParkedEmailsFilter) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.ReplayParkedEmails")
+	defer span.Finish()
+
+	replayed, err := e.publisher.ReplayParked(ctx, filter)
+	if err != nil {
+		return replayed, errors.Wrap(err, "publisher.ReplayParked")
+	}
+
+	e.logger.Infof("Replayed parked emails: %d", replayed)
+	return replayed, nil
+}
+
+// Purge parked emails matching the filter, purging all of them requires the all flag
+func (e *EmailUseCase) PurgeParkedEmails(ctx context.Context, filter *models.ParkedEmailsFilter, all bool) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "EmailsUseCase.PurgeParkedEmails")
+	defer span.Finish()
+
+	// empty filter purges the whole parking queue, so it has to be requested explicitly
+	if !all && filter.IsEmpty() {
+		return 0, grpc_errors.ErrEmptyParkedFilter
+	}
+
+	purged, err := e.publisher.PurgeParked(ctx, filter)
+	// emails purged before the error are gone as well
+	for _, parked := range purged {
+		if parked.Email != nil {
+			e.removeAttachments(ctx, parked.Email)
+		}
+	}
+	if err != nil {
+		return len(purged), errors.Wrap(err, "publisher.PurgeParked")
+	}
+
+	e.logger.Infof("Purged parked emails: %d", len(purged))
+	return len(purged), nil
 }
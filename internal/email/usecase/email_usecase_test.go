@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rmq_service/config"
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) InitLogger() {}
+func (nopLogger) Debug(args ...interface{}) {}
+func (nopLogger) Debugf(template string, args ...interface{}) {}
+func (nopLogger) Info(args ...interface{}) {}
+func (nopLogger) Infof(template string, args ...interface{}) {}
+func (nopLogger) Warn(args ...interface{}) {}
+func (nopLogger) Warnf(template string, args ...interface{}) {}
+func (nopLogger) Error(args ...interface{}) {}
+func (nopLogger) Errorf(template string, args ...interface{}) {}
+func (nopLogger) DPanic(args ...interface{}) {}
+func (nopLogger) DPanicf(template string, args ...interface{}) {}
+func (nopLogger) Fatal(args ...interface{}) {}
+func (nopLogger) Fatalf(template string, args ...interface{}) {}
+
+// Repository of the stored emails, methods which are not overridden panic
+type fakeEmailsRepo struct {
+	email.EmailsRepository
+	scheduled 	[]*models.ScheduledEmail
+	completed 	[]uuid.UUID
+	rescheduled []uuid.UUID
+}
+
+func (r *fakeEmailsRepo) ClaimDueScheduledEmails(ctx context.Context, limit int, lease time.Duration) ([]*models.ScheduledEmail, error) {
+	return r.scheduled, nil
+}
+
+func (r *fakeEmailsRepo) CompleteScheduledEmail(ctx context.Context, id uuid.UUID) error {
+	r.completed = append(r.completed, id)
+	return nil
+}
+
+func (r *fakeEmailsRepo) RescheduleEmail(ctx context.Context, s *models.ScheduledEmail) error {
+	r.rescheduled = append(r.rescheduled, s.EmailID)
+	return nil
+}
+
+// Publisher failing every publish when err is set
+type fakePublisher struct {
+	email.EmailsPublisher
+	err 			error
+	published int
+}
+
+func (p *fakePublisher) Publish(body []byte, contentType, messageID string, priority uint8) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published++
+	return nil
+}
+
+func (p *fakePublisher) PublishEvent(event *models.LifecycleEvent) error {
+	return nil
+}
+
+func newTestEmailUseCase(repo email.EmailsRepository, publisher email.EmailsPublisher) *EmailUseCase {
+	return NewEmailUseCase(nil, repo, nopLogger{}, &config.Config{}, publisher, nil, nil, nil, nil, nil, nil, nil)
+}
+
+func TestDispatchScheduledEmailsReschedulesOnPublishFailure(t *testing.T) {
+	repo := &fakeEmailsRepo{scheduled: []*models.ScheduledEmail{{EmailID: uuid.New()}, {EmailID: uuid.New()}}}
+	publisher := &fakePublisher{err: errors.New("channel closed")}
+
+	dispatched, err := newTestEmailUseCase(repo, publisher).DispatchScheduledEmails(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchScheduledEmails: %v", err)
+	}
+	if dispatched != 0 {
+		t.Fatalf("dispatched = %d, want 0", dispatched)
+	}
+	if len(repo.completed) != 2 || len(repo.rescheduled) != 2 {
+		t.Fatalf("completed %v, rescheduled %v, want both emails", repo.completed, repo.rescheduled)
+	}
+	for i, s := range repo.scheduled {
+		if repo.rescheduled[i] != s.EmailID {
+			t.Errorf("rescheduled %v, want %v", repo.rescheduled[i], s.EmailID)
+		}
+	}
+
+	publisher.err = nil
+	repo.rescheduled = nil
+	if dispatched, err := newTestEmailUseCase(repo, publisher).DispatchScheduledEmails(context.Background()); err != nil || dispatched != 2 {
+		t.Fatalf("DispatchScheduledEmails = %d, %v, want 2", dispatched, err)
+	}
+	if len(repo.rescheduled) != 0 {
+		t.Fatalf("rescheduled %v after successful publish", repo.rescheduled)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"rmq_service/config"
+	"rmq_service/internal/tenant"
 	"rmq_service/pkg/grpc_errors"
 	"rmq_service/pkg/logger"
 	"rmq_service/pkg/metrics"
@@ -18,11 +19,17 @@ type InteceptorManager struct {
 	logger 	logger.Logger
 	cfg 		*config.Config
 	metr 		metrics.Metrics
+	tenantsUC tenant.TenantsUseCase
 }
 
 // InterceptorManager Constructor
-func NewInterceptorManager(logger logger.Logger, cfg *config.Config, metr metrics.Metrics) *InteceptorManager {
-	return &InteceptorManager{logger: logger, cfg: cfg, metr: metr}
+func NewInterceptorManager(
+	logger logger.Logger,
+	cfg *config.Config,
+	metr metrics.Metrics,
+	tenantsUC tenant.TenantsUseCase,
+) *InteceptorManager {
+	return &InteceptorManager{logger: logger, cfg: cfg, metr: metr, tenantsUC: tenantsUC}
 }
 
 // Logger Interceptor
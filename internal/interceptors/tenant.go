@@ -0,0 +1,96 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/grpc_errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyMetadata 		= "x-api-key"
+	adminKeyMetadata 	= "x-admin-key"
+)
+
+// Admin RPCs authorized by the admin key instead of tenant API key,
+// bounce reports suppress recipients of all tenants and parking queue holds emails of all tenants
+var tenantAdminMethods = map[string]struct{}{
+	"/emailService.EmailService/CreateTenant": 				{},
+	"/emailService.EmailService/UpdateTenant": 				{},
+	"/emailService.EmailService/GetTenant": 					{},
+	"/emailService.EmailService/ListTenants": 				{},
+	"/emailService.EmailService/RotateTenantApiKey": 	{},
+	"/emailService.EmailService/ProcessBounceReport": 	{},
+	"/emailService.EmailService/ListParkedEmails": 		{},
+	"/emailService.EmailService/GetParkedEmail": 			{},
+	"/emailService.EmailService/ReplayParkedEmails": 	{},
+	"/emailService.EmailService/PurgeParkedEmails": 	{},
+}
+
+// Tenant Interceptor, tenant of the API key is put into the request context.
+// Requests without API key belong to the default tenant unless tenants are required.
+func (im *InteceptorManager) Tenant(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if _, ok := tenantAdminMethods[info.FullMethod]; ok {
+		if err := im.authorizeAdmin(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	apiKey := metadataValue(ctx, apiKeyMetadata)
+	if apiKey == "" {
+		if im.cfg.Tenants.Required {
+			return nil, status.Errorf(codes.Unauthenticated, "InterceptorManager.Tenant: %v", grpc_errors.ErrNoCtxMetadata)
+		}
+		return handler(ctx, req)
+	}
+
+	t, err := im.tenantsUC.Authenticate(ctx, apiKey)
+	if err != nil {
+		im.logger.Errorf("tenantsUC.Authenticate: %v", err)
+		return nil, status.Errorf(grpc_errors.ParseGRPCErrStatusCode(err), "tenantsUC.Authenticate: %v", err)
+	}
+
+	return handler(tenant.WithTenant(ctx, t), req)
+}
+
+// Check admin key of the request, admin RPCs are disabled without configured admin key
+func (im *InteceptorManager) authorizeAdmin(ctx context.Context) error {
+	if im.cfg.Tenants.AdminKey == "" {
+		return status.Error(codes.PermissionDenied, "InterceptorManager.authorizeAdmin: tenant admin is disabled")
+	}
+
+	adminKey := metadataValue(ctx, adminKeyMetadata)
+	if adminKey == "" {
+		return status.Errorf(codes.Unauthenticated, "InterceptorManager.authorizeAdmin: %v", grpc_errors.ErrNoCtxMetadata)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(adminKey), []byte(im.cfg.Tenants.AdminKey)) != 1 {
+		return status.Errorf(codes.Unauthenticated, "InterceptorManager.authorizeAdmin: %v", grpc_errors.ErrInvalidAPIKey)
+	}
+
+	return nil
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
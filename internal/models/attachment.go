@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"rmq_service/pkg/mime_types"
+
+	"github.com/google/uuid"
+)
+
+// Blob storage prefix of attachments without tenant
+const defaultAttachmentsPrefix = "default"
+
+// Email attachment, content is either inlined or stored in the blob storage under BlobKey
+type Attachment struct {
+	Filename 		string `json:"filename" validate:"required,lte=250"`
+	ContentType string `json:"contentType,omitempty" validate:"lte=250"`
+	Content 		[]byte `json:"content,omitempty"`
+	BlobKey 		string `json:"blobKey,omitempty" validate:"lte=1024"`
+	Size 				int64  `json:"size"`
+	Inline 			bool 	 `json:"inline,omitempty"`
+}
+
+// Prepare attachment to sending
+func (a *Attachment) Prepare() {
+	if a.ContentType == "" {
+		a.ContentType = mime_types.MIMEOctetStream
+	}
+
+	if len(a.Content) > 0 {
+		a.Size = int64(len(a.Content))
+	}
+}
+
+// Blob storage prefix of the tenant attachments, blob keys of other prefixes are never read for the tenant
+func AttachmentsTenantPrefix(tenantID *uuid.UUID) string {
+	if tenantID == nil {
+		return defaultAttachmentsPrefix + "/"
+	}
+	return tenantID.String() + "/"
+}
+
+// Blob storage prefix of the email attachments offloaded by the service, removed after the email is done
+func AttachmentsEmailPrefix(tenantID *uuid.UUID, emailID uuid.UUID) string {
+	return AttachmentsTenantPrefix(tenantID) + emailID.String() + "/"
+}
+
+// Check if blob key is in the tenant prefix and has no relative path elements
+func IsTenantBlobKey(tenantID *uuid.UUID, key string) bool {
+	return strings.HasPrefix(key, AttachmentsTenantPrefix(tenantID)) && path.Clean(key) == key
+}
+
+// Check attachments size limits, zero limit means no limit
+func ValidateAttachments(attachments []*Attachment, maxSize, maxTotalSize int64) error {
+	var total int64
+	for _, a := range attachments {
+		if len(a.Content) == 0 && a.BlobKey == "" {
+			return fmt.Errorf("Validate attachment %s: content or blob key is required", a.Filename)
+		}
+
+		if maxSize > 0 && a.Size > maxSize {
+			return fmt.Errorf("Validate attachment %s: size %d exceeds %d bytes", a.Filename, a.Size, maxSize)
+		}
+		total += a.Size
+	}
+
+	if maxTotalSize > 0 && total > maxTotalSize {
+		return fmt.Errorf("Validate attachments: total size %d exceeds %d bytes", total, maxTotalSize)
+	}
+
+	return nil
+}
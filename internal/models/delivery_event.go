@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Custom variable carrying the email id through the provider API to its webhook events
+const DeliveryEventEmailIDVariable = "email_id"
+
+// Delivery event of the email recipient reported by the provider webhook or the bounce report,
+// recorded as the email event of its type
+type DeliveryEvent struct {
+	EmailID 	uuid.UUID `json:"emailId"`
+	// provider message id, event without email id is matched to the email by it
+	MessageID string 		`json:"messageId,omitempty"`
+	// delivered, deferred, bounce, soft_bounce or complaint
+	Type 			string 		`json:"type"`
+	Recipient string 		`json:"recipient"`
+	Reason 		string 		`json:"reason,omitempty"`
+	// id of the provider webhook event, events delivered again by the provider are recorded once
+	ProviderEventID string `json:"providerEventId,omitempty"`
+}
+
+// Email status the event moves the email to, other events leave the status as it is
+func (e *DeliveryEvent) GetEmailStatus() string {
+	switch e.Type {
+	case EmailEventDelivered:
+		return EmailStatusDelivered
+	case EmailEventBounce:
+		return EmailStatusBounced
+	}
+	return ""
+}
+
+// Suppression reason of the recipient, only hard bounces and complaints suppress the recipient
+func (e *DeliveryEvent) GetSuppressionReason() string {
+	switch e.Type {
+	case EmailEventBounce:
+		return SuppressionReasonHardBounce
+	case EmailEventComplaint:
+		return SuppressionReasonComplaint
+	}
+	return ""
+}
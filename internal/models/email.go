@@ -3,6 +3,8 @@ package models
 import (
 	"context"
 	"fmt"
+	"mime"
+	"regexp"
 	"strings"
 	"time"
 
@@ -17,37 +19,251 @@ import (
 type Email struct {
 	EmailID				uuid.UUID `json:"emailId" db:"email_id" validate:"omitempty"`
 	To      			[]string  `json:"to" db:"to" validate:"required"`
+	Cc 						[]string 	`json:"cc,omitempty" db:"cc"`
+	Bcc 					[]string 	`json:"bcc,omitempty" db:"bcc"`
+	ReplyTo 			string 		`json:"replyTo,omitempty" db:"reply_to" validate:"omitempty,email"`
+	Headers 			map[string]string `json:"headers,omitempty" db:"headers" validate:"lte=50"`
 	From 					string  	`json:"from,omitempty" db:"from" validate:"required,email"`
 	Body 					string 		`json:"body" db:"body" validate:"required"`
+	TextBody 			string 		`json:"textBody,omitempty" db:"text_body"`
 	Subject 			string  	`json:"subject" db:"subject" validate:"required,lte=250"`
-	ContentType		string 		`json:"contentType,omitempty" db:"content_type" validate:"lte=250"`
+	ContentType		string 		`json:"contentType,omitempty" db:"content_type" validate:"required,lte=250"`
+	IdempotencyKey string 	`json:"idempotencyKey,omitempty" db:"idempotency_key" validate:"lte=255"`
+	Category 			string 		`json:"category,omitempty" db:"category" validate:"lte=50"`
+	Priority 			string 		`json:"priority,omitempty" db:"priority" validate:"omitempty,oneof=low normal high"`
+	// opt-in open and click tracking of html body
+	Track 				bool 			`json:"track,omitempty"`
+	// status webhook of the email, tenant webhook is used when empty
+	WebhookURL 		string 		`json:"webhookUrl,omitempty" db:"webhook_url" validate:"omitempty,http_url,lte=2000"`
+	SendAt 				*time.Time `json:"sendAt,omitempty" db:"send_at"`
+	TemplateID 		*uuid.UUID `json:"templateId,omitempty"`
+	TemplateVersion int 		`json:"templateVersion,omitempty"`
+	Variables 		map[string]string `json:"variables,omitempty"`
+	Attachments 	[]*Attachment `json:"attachments,omitempty" validate:"omitempty,dive"`
+	BatchID 			*uuid.UUID `json:"batchId,omitempty" db:"batch_id"`
+	TenantID 			*uuid.UUID `json:"tenantId,omitempty" db:"tenant_id"`
+	Provider 			string 		`json:"provider,omitempty" db:"provider"`
+	ProviderMessageID string `json:"providerMessageId,omitempty" db:"provider_message_id"`
+	Status 				string 		`json:"status,omitempty" db:"status"`
+	StatusReason 	string 		`json:"statusReason,omitempty" db:"status_reason"`
 	CreatedAt 		time.Time `json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt 		time.Time `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+const (
+	maxHeaderValueLen = 998
+)
+
+var customHeaderNameRegex = regexp.MustCompile(`(?i)^X-[A-Za-z0-9-]+$`)
+
+// Content types allowed for email body
+var allowedBodyContentTypes = map[string]struct{}{
+	mime_types.MIMETextPlain: {},
+	mime_types.MIMETextHTML: 	{},
 }
 
 // Get string from addresses
 func (e *Email) GetToString() string {
-	return strings.Join(e.To, ",")
+	return joinAddresses(e.To)
+}
+
+// Get string from carbon copy addresses
+func (e *Email) GetCcString() string {
+	return joinAddresses(e.Cc)
+}
+
+// Get string from blind carbon copy addresses
+func (e *Email) GetBccString() string {
+	return joinAddresses(e.Bcc)
+}
+
+// Get all recipients addresses
+func (e *Email) GetRecipients() []string {
+	recipients := make([]string, 0, len(e.To)+len(e.Cc)+len(e.Bcc))
+	recipients = append(recipients, e.To...)
+	recipients = append(recipients, e.Cc...)
+	return append(recipients, e.Bcc...)
+}
+
+// Get domain of the sender address
+func (e *Email) GetFromDomain() string {
+	return e.From[strings.LastIndex(e.From, "@")+1:]
 }
 
 // Prepare Email to creation
 func (e *Email) PrepareAndValidate(ctx context.Context) error {
 	e.From = strings.TrimSpace(strings.ToLower(e.From))
-	
-	for i, mail := range e.To {
-		if !utils.ValidateEmail(e.To[i]) {
-			return fmt.Errorf("invalid email: %s", mail)
+	e.ReplyTo = strings.TrimSpace(strings.ToLower(e.ReplyTo))
+	e.Category = strings.TrimSpace(strings.ToLower(e.Category))
+	e.Priority = strings.TrimSpace(strings.ToLower(e.Priority))
+	if e.Priority == "" {
+		e.Priority = EmailPriorityNormal
+	}
+
+	for _, addresses := range [][]string{e.To, e.Cc, e.Bcc} {
+		if err := prepareAddresses(addresses); err != nil {
+			return err
 		}
+	}
+
+	if e.ReplyTo != "" && !utils.ValidateEmail(e.ReplyTo) {
+		return fmt.Errorf("Validate reply to: invalid email: %s", e.ReplyTo)
+	}
 
-		e.To[i] = strings.TrimSpace(strings.ToLower(e.To[i]))
+	if err := validateCustomHeaders(e.Headers); err != nil {
+		return err
+	}
+
+	for _, a := range e.Attachments {
+		a.Prepare()
+	}
+
+	if err := e.prepareBody(); err != nil {
+		return err
 	}
 
-	e.ContentType = mime_types.MIMEApplicationJSON
 	return utils.ValidateStruct(ctx, e)
 }
 
+// Set subject and body from the rendered template
+func (e *Email) SetRenderedTemplate(rendered *RenderedTemplate) {
+	e.Subject = rendered.Subject
+	if rendered.HTMLBody != "" {
+		e.ContentType = mime_types.MIMETextHTML
+		e.Body = rendered.HTMLBody
+		e.TextBody = rendered.TextBody
+		return
+	}
+
+	e.ContentType = mime_types.MIMETextPlain
+	e.Body = rendered.TextBody
+	e.TextBody = ""
+}
+
+// Remove suppressed recipients, returns description of every removed recipient
+func (e *Email) DropSuppressed(suppressed map[string]*Suppression) []string {
+	dropped := make([]string, 0, len(suppressed))
+	keep := func(addresses []string) []string {
+		kept := addresses[:0]
+		for _, address := range addresses {
+			if s, ok := suppressed[address]; ok {
+				dropped = append(dropped, fmt.Sprintf("%s suppressed by %s (%s)", address, s.Address, s.Reason))
+				continue
+			}
+			kept = append(kept, address)
+		}
+		return kept
+	}
+
+	e.To = keep(e.To)
+	e.Cc = keep(e.Cc)
+	e.Bcc = keep(e.Bcc)
+	return dropped
+}
+
+// Check if email body is html
+func (e *Email) IsHTML() bool {
+	return e.ContentType == mime_types.MIMETextHTML
+}
+
+// Check content type against the allowlist and generate plain text alternative for html body
+func (e *Email) prepareBody() error {
+	contentType, err := parseBodyContentType(e.ContentType)
+	if err != nil {
+		return err
+	}
+	e.ContentType = contentType
+
+	if !e.IsHTML() {
+		if e.TextBody != "" {
+			return fmt.Errorf("Validate text body: only allowed with %s content type", mime_types.MIMETextHTML)
+		}
+		return nil
+	}
+
+	if e.TextBody == "" {
+		e.TextBody = utils.HTMLToText(e.Body)
+	}
+	return nil
+}
+
+// Check body content type against the allowlist, plain text is used by default
+func parseBodyContentType(contentType string) (string, error) {
+	if contentType == "" {
+		return mime_types.MIMETextPlain, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("Validate content type: %w", err)
+	}
+
+	if _, ok := allowedBodyContentTypes[mediaType]; !ok {
+		return "", fmt.Errorf("Validate content type: %s is not allowed", contentType)
+	}
+	return mediaType, nil
+}
+
+// Check if email must wait for its send time
+func (e *Email) IsScheduled() bool {
+	return e.SendAt != nil && e.SendAt.After(time.Now())
+}
+
 // Set to array from string value
 func (e *Email) SetToFromString(to string) {
-	e.To = strings.Split(to, ", ")
+	e.To = splitAddresses(to)
+}
+
+// Set cc array from string value
+func (e *Email) SetCcFromString(cc string) {
+	e.Cc = splitAddresses(cc)
+}
+
+// Set bcc array from string value
+func (e *Email) SetBccFromString(bcc string) {
+	e.Bcc = splitAddresses(bcc)
+}
+
+// Validate and normalize addresses
+func prepareAddresses(addresses []string) error {
+	for i, mail := range addresses {
+		addresses[i] = strings.TrimSpace(strings.ToLower(mail))
+
+		if !utils.ValidateEmail(addresses[i]) {
+			return fmt.Errorf("Validate addresses: invalid email: %s", mail)
+		}
+	}
+	return nil
+}
+
+// Only X- headers can be set by callers, so they never override the standard ones
+func validateCustomHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !customHeaderNameRegex.MatchString(name) {
+			return fmt.Errorf("Validate headers: invalid header name: %s", name)
+		}
+
+		if len(value) > maxHeaderValueLen || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("Validate headers: invalid header value: %s", name)
+		}
+	}
+	return nil
+}
+
+func joinAddresses(addresses []string) string {
+	return strings.Join(addresses, ",")
+}
+
+func splitAddresses(addresses string) []string {
+	if addresses == "" {
+		return []string{}
+	}
+
+	split := strings.Split(addresses, ",")
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+	return split
 }
 
 // Emails list with pangination
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"rmq_service/pkg/utils"
+
+	"rmq_service/pkg/mime_types"
+
+	"github.com/google/uuid"
+)
+
+// Bulk email recipient with personal template variables
+type BulkRecipient struct {
+	To 							string 						`json:"to" validate:"required,email"`
+	Variables 			map[string]string `json:"variables,omitempty"`
+	IdempotencyKey 	string 						`json:"idempotencyKey,omitempty" validate:"lte=255"`
+}
+
+// Bulk email, shared subject and body are rendered for every recipient
+type BulkEmail struct {
+	Subject 				string 						`json:"subject" validate:"required_without=TemplateID,lte=250"`
+	Body 						string 						`json:"body" validate:"required_without=TemplateID"`
+	TextBody 				string 						`json:"textBody,omitempty"`
+	ContentType 		string 						`json:"contentType,omitempty"`
+	TemplateID 			*uuid.UUID 				`json:"templateId,omitempty"`
+	TemplateVersion int 							`json:"templateVersion,omitempty"`
+	ReplyTo 				string 						`json:"replyTo,omitempty" validate:"omitempty,email"`
+	Headers 				map[string]string `json:"headers,omitempty"`
+	SendAt 					*time.Time 				`json:"sendAt,omitempty"`
+	Category 				string 						`json:"category,omitempty"`
+	Priority 				string 						`json:"priority,omitempty" validate:"omitempty,oneof=low normal high"`
+	Track 					bool 							`json:"track,omitempty"`
+	WebhookURL 			string 						`json:"webhookUrl,omitempty" validate:"omitempty,http_url,lte=2000"`
+	Recipients 			[]*BulkRecipient 	`json:"recipients" validate:"required,min=1,dive"`
+}
+
+// Validate bulk email, template variables are checked on rendering
+func (b *BulkEmail) PrepareAndValidate(ctx context.Context) error {
+	b.ReplyTo = strings.TrimSpace(strings.ToLower(b.ReplyTo))
+	// bulk emails must not hold up the transactional ones
+	b.Priority = strings.TrimSpace(strings.ToLower(b.Priority))
+	if b.Priority == "" {
+		b.Priority = EmailPriorityLow
+	}
+
+	if b.TemplateID == nil {
+		contentType, err := parseBodyContentType(b.ContentType)
+		if err != nil {
+			return err
+		}
+		b.ContentType = contentType
+	}
+
+	if err := validateCustomHeaders(b.Headers); err != nil {
+		return err
+	}
+
+	return utils.ValidateStruct(ctx, b)
+}
+
+// Get inline template from bulk email subject and body
+func (b *BulkEmail) GetTemplate() *Template {
+	t := &Template{Name: "bulk", Subject: b.Subject}
+	if b.ContentType == mime_types.MIMETextHTML {
+		t.HTMLBody = b.Body
+		t.TextBody = b.TextBody
+	} else {
+		t.TextBody = b.Body
+	}
+	return t
+}
+
+// Build recipient email from the rendered template
+func (b *BulkEmail) NewEmail(recipient *BulkRecipient, rendered *RenderedTemplate) *Email {
+	email := &Email{
+		To: 						[]string{recipient.To},
+		ReplyTo: 				b.ReplyTo,
+		Headers: 				b.Headers,
+		SendAt: 				b.SendAt,
+		Category: 			b.Category,
+		Priority: 			b.Priority,
+		Track: 					b.Track,
+		WebhookURL: 		b.WebhookURL,
+		TemplateID: 		b.TemplateID,
+		TemplateVersion: b.TemplateVersion,
+		Variables: 			recipient.Variables,
+		IdempotencyKey: recipient.IdempotencyKey,
+	}
+	email.SetRenderedTemplate(rendered)
+	return email
+}
+
+// Emails batch created by bulk send, failed recipients are only reported by the bulk send
+type EmailBatch struct {
+	BatchID 	uuid.UUID 					`json:"batchId" db:"batch_id"`
+	Total 		int 								`json:"total" db:"total"`
+	CreatedAt time.Time 					`json:"createdAt" db:"created_at"`
+	Failed 		[]*BulkFailedRecipient `json:"failed,omitempty" db:"-"`
+}
+
+// Bulk recipient which email could not be published
+type BulkFailedRecipient struct {
+	To 		string `json:"to"`
+	Error string `json:"error"`
+}
+
+// Emails batch progress, emails count by status
+type EmailBatchProgress struct {
+	EmailBatch
+	Statuses map[string]uint64 `json:"statuses"`
+}
+
+// Get count of emails which are not processed yet
+func (p *EmailBatchProgress) Pending() uint64 {
+	var pending uint64
+	for status, count := range p.Statuses {
+		if IsPendingEmailStatus(status) {
+			pending += count
+		}
+	}
+	return pending
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email event types
+const (
+	EmailEventOpen 				= "open"
+	EmailEventClick 			= "click"
+	EmailEventDelivered 	= "delivered"
+	EmailEventDeferred 		= "deferred"
+	EmailEventBounce 			= "bounce"
+	EmailEventSoftBounce 	= "soft_bounce"
+	EmailEventComplaint 	= "complaint"
+)
+
+// Event recorded against the email
+type EmailEvent struct {
+	EventID 	int64 		`json:"eventId" db:"event_id"`
+	EmailID 	uuid.UUID `json:"emailId" db:"email_id"`
+	Type 			string 		`json:"type" db:"type"`
+	// clicked link
+	URL 			string 		`json:"url,omitempty" db:"url"`
+	UserAgent string 		`json:"userAgent,omitempty" db:"user_agent"`
+	IP 				string 		`json:"ip,omitempty" db:"ip"`
+	// recipient of the delivery event and the reported reason
+	Recipient string 		`json:"recipient,omitempty" db:"recipient"`
+	Reason 		string 		`json:"reason,omitempty" db:"reason"`
+	// id of the provider webhook event, unique across the events
+	ProviderEventID string `json:"providerEventId,omitempty" db:"provider_event_id"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Signed open or click tracking token payload
+type TrackingToken struct {
+	EmailID uuid.UUID `json:"e"`
+	URL 		string 		`json:"u,omitempty"`
+}
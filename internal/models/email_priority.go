@@ -0,0 +1,26 @@
+package models
+
+// Email delivery priorities, transactional emails go before the bulk ones
+const (
+	EmailPriorityLow 		= "low"
+	EmailPriorityNormal = "normal"
+	EmailPriorityHigh 	= "high"
+)
+
+// Max RabbitMQ priority of the emails queue
+const MaxEmailQueuePriority uint8 = 3
+
+// RabbitMQ message priorities of the email priorities
+var emailQueuePriorities = map[string]uint8{
+	EmailPriorityLow: 		1,
+	EmailPriorityNormal: 	2,
+	EmailPriorityHigh: 		3,
+}
+
+// Get RabbitMQ message priority, unknown priority is treated as normal
+func GetEmailQueuePriority(priority string) uint8 {
+	if p, ok := emailQueuePriorities[priority]; ok {
+		return p
+	}
+	return emailQueuePriorities[EmailPriorityNormal]
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email lifecycle statuses
+const (
+	EmailStatusAccepted 	= "accepted"
+	EmailStatusScheduled 	= "scheduled"
+	EmailStatusQueued 		= "queued"
+	EmailStatusSending 		= "sending"
+	EmailStatusSent 			= "sent"
+	EmailStatusDelivered 	= "delivered"
+	EmailStatusFailed 		= "failed"
+	EmailStatusBounced 		= "bounced"
+	EmailStatusCancelled 	= "cancelled"
+	EmailStatusSuppressed = "suppressed"
+)
+
+// Allowed transitions between email statuses
+var emailStatusTransitions = map[string][]string{
+	EmailStatusAccepted: 	{EmailStatusScheduled, EmailStatusQueued, EmailStatusFailed, EmailStatusCancelled},
+	EmailStatusScheduled: {EmailStatusQueued, EmailStatusFailed, EmailStatusCancelled},
+	EmailStatusQueued: 		{EmailStatusSending, EmailStatusFailed, EmailStatusCancelled},
+	// rate limited emails go back to the queue
+	EmailStatusSending: 	{EmailStatusSent, EmailStatusFailed, EmailStatusSuppressed, EmailStatusQueued},
+	// delivery and bounces are reported by the providers after the email is sent
+	EmailStatusSent: 			{EmailStatusDelivered, EmailStatusBounced},
+	EmailStatusDelivered: {EmailStatusBounced},
+	// failed deliveries are retried from the delay queues
+	EmailStatusFailed: 		{EmailStatusSending},
+	EmailStatusBounced: 	{},
+	EmailStatusCancelled: {},
+	EmailStatusSuppressed: {},
+}
+
+// Check if email status can be changed to the given one
+func CanTransitEmailStatus(from, to string) bool {
+	for _, status := range emailStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if email status is final
+func IsFinalEmailStatus(status string) bool {
+	next, ok := emailStatusTransitions[status]
+	return ok && len(next) == 0
+}
+
+// Check if email is still waiting for delivery
+func IsPendingEmailStatus(status string) bool {
+	switch status {
+	case EmailStatusAccepted, EmailStatusScheduled, EmailStatusQueued, EmailStatusSending:
+		return true
+	}
+	return false
+}
+
+// Email status transition
+type EmailStatusTransition struct {
+	ID 				int64 		`json:"id" db:"id"`
+	EmailID 	uuid.UUID `json:"emailId" db:"email_id"`
+	Status 		string 		`json:"status" db:"status"`
+	Reason 		string 		`json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
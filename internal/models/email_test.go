@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"rmq_service/pkg/grpc_errors"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestEmailPrepareAndValidateInvalidArgument(t *testing.T) {
+	cases := map[string]func(e *Email){
+		"cc": 					func(e *Email) { e.Cc = []string{"not an address"} },
+		"bcc": 					func(e *Email) { e.Bcc = []string{"bcc@"} },
+		"reply to": 		func(e *Email) { e.ReplyTo = "reply-to" },
+		"header name": 	func(e *Email) { e.Headers = map[string]string{"Subject": "override"} },
+		"header value": func(e *Email) { e.Headers = map[string]string{"X-Campaign": "a\r\nBcc: x@example.com"} },
+		"content type": func(e *Email) { e.ContentType = "application/pdf" },
+	}
+
+	for name, invalidate := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &Email{
+				To: 			[]string{"to@example.com"},
+				From: 		"from@example.com",
+				Subject: 	"subject",
+				Body: 		"body",
+			}
+			invalidate(e)
+
+			err := e.PrepareAndValidate(context.Background())
+			if err == nil {
+				t.Fatal("PrepareAndValidate succeeded")
+			}
+			if code := grpc_errors.ParseGRPCErrStatusCode(err); code != codes.InvalidArgument {
+				t.Errorf("status code of %v = %v, want InvalidArgument", err, code)
+			}
+		})
+	}
+}
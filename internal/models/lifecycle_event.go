@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version of the lifecycle event schema, bumped on incompatible changes only
+const LifecycleEventSchemaVersion = 1
+
+// Email lifecycle event published to the events exchange on every status change,
+// routing key is the event type, e.g. email.sent or email.failed
+type LifecycleEvent struct {
+	SchemaVersion int 				`json:"schemaVersion"`
+	EventID 			uuid.UUID 	`json:"eventId"`
+	Type 					string 			`json:"type"`
+	EmailID 			uuid.UUID 	`json:"emailId"`
+	TenantID 			*uuid.UUID 	`json:"tenantId,omitempty"`
+	Status 				string 			`json:"status"`
+	// bcc recipients are left out like in the stored email
+	Recipients 		[]string 		`json:"recipients"`
+	// cause of the failed status
+	Error 				string 			`json:"error,omitempty"`
+	// reason of any other status, e.g. suppressed recipients
+	StatusReason 	string 			`json:"statusReason,omitempty"`
+	Provider 			string 			`json:"provider,omitempty"`
+	ProviderMessageID string 	`json:"providerMessageId,omitempty"`
+	CreatedAt 		time.Time 	`json:"createdAt"`
+	OccurredAt 		time.Time 	`json:"occurredAt"`
+}
+
+// Lifecycle event of the email current status
+func NewLifecycleEvent(email *Email) *LifecycleEvent {
+	event := &LifecycleEvent{
+		SchemaVersion: 			LifecycleEventSchemaVersion,
+		EventID: 						uuid.New(),
+		Type: 							GetLifecycleEventType(email.Status),
+		EmailID: 						email.EmailID,
+		TenantID: 					email.TenantID,
+		Status: 						email.Status,
+		Recipients: 				append(append([]string{}, email.To...), email.Cc...),
+		Provider: 					email.Provider,
+		ProviderMessageID: 	email.ProviderMessageID,
+		CreatedAt: 					email.CreatedAt,
+		OccurredAt: 				time.Now().UTC(),
+	}
+
+	if email.Status == EmailStatusFailed {
+		event.Error = email.StatusReason
+	} else {
+		event.StatusReason = email.StatusReason
+	}
+	return event
+}
+
+// Lifecycle event type and routing key of the email status
+func GetLifecycleEventType(status string) string {
+	return "email." + status
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Email message parked after the last delivery attempt
+type ParkedEmail struct {
+	MessageID 		string 		`json:"messageId"`
+	Email 				*Email 		`json:"email,omitempty"`
+	FailureReason string 		`json:"failureReason"`
+	Attempts 			int64 		`json:"attempts"`
+	FailedAt 			time.Time `json:"failedAt,omitempty"`
+}
+
+// Parked emails filter, empty fields match everything
+type ParkedEmailsFilter struct {
+	MessageID 			string `json:"messageId,omitempty"`
+	To 							string `json:"to,omitempty"`
+	ReasonContains 	string `json:"reasonContains,omitempty"`
+	Limit 					int 	 `json:"limit,omitempty"`
+}
+
+// Check if filter has no criteria and matches every parked email
+func (f *ParkedEmailsFilter) IsEmpty() bool {
+	return f == nil || (f.MessageID == "" && f.To == "" && f.ReasonContains == "")
+}
+
+// Check if parked email matches the filter
+func (f *ParkedEmailsFilter) Match(p *ParkedEmail) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.MessageID != "" && f.MessageID != p.MessageID {
+		return false
+	}
+
+	if f.ReasonContains != "" && !strings.Contains(strings.ToLower(p.FailureReason), strings.ToLower(f.ReasonContains)) {
+		return false
+	}
+
+	if f.To != "" {
+		if p.Email == nil {
+			return false
+		}
+		for _, to := range p.Email.To {
+			if strings.EqualFold(to, f.To) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Quota scopes
+const (
+	QuotaScopeTenant = "tenant"
+	QuotaScopeSender = "sender"
+)
+
+// Quota periods, periods start at UTC midnight
+const (
+	QuotaPeriodDay 		= "day"
+	QuotaPeriodMonth 	= "month"
+)
+
+// Key of the default tenant usage
+const DefaultTenantQuotaKey = "default"
+
+// Key of the sender usage, the same sender of different tenants has separate quotas
+func SenderQuotaKey(tenantKey, sender string) string {
+	return tenantKey + "/" + strings.ToLower(sender)
+}
+
+// Recipients accepted in the quota period of the tenant or sender
+type QuotaUsage struct {
+	Scope 			string 		`json:"scope" db:"scope"`
+	Key 				string 		`json:"key" db:"key"`
+	Period 			string 		`json:"period" db:"period"`
+	PeriodStart time.Time `json:"periodStart" db:"period_start"`
+	Used 				int64 		`json:"used" db:"used"`
+	// zero limit is unlimited
+	Limit 			int64 		`json:"limit"`
+}
+
+// Usage of the current period
+func NewQuotaUsage(scope, key, period string, limit int64, now time.Time) *QuotaUsage {
+	return &QuotaUsage{
+		Scope: 				scope,
+		Key: 					key,
+		Period: 			period,
+		PeriodStart: 	GetQuotaPeriodStart(period, now),
+		Limit: 				limit,
+	}
+}
+
+// Check if usage is limited
+func (u *QuotaUsage) IsLimited() bool {
+	return u.Limit > 0
+}
+
+// Get recipients left in the period, zero for unlimited usage
+func (u *QuotaUsage) GetRemaining() int64 {
+	if !u.IsLimited() || u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// Get time the next period starts
+func (u *QuotaUsage) GetResetsAt() time.Time {
+	if u.Period == QuotaPeriodMonth {
+		return u.PeriodStart.AddDate(0, 1, 0)
+	}
+	return u.PeriodStart.AddDate(0, 0, 1)
+}
+
+// Get start of the period which contains the time
+func GetQuotaPeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == QuotaPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
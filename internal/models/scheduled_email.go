@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email waiting in the scheduler for its send time
+type ScheduledEmail struct {
+	EmailID 		uuid.UUID `json:"emailId" db:"email_id"`
+	SendAt 			time.Time `json:"sendAt" db:"send_at"`
+	Payload 		[]byte 		`json:"payload" db:"payload"`
+	ContentType string 		`json:"contentType" db:"content_type"`
+	MessageID 	string 		`json:"messageId" db:"message_id"`
+	Priority 		string 		`json:"priority" db:"priority"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Suppression reasons
+const (
+	SuppressionReasonHardBounce 	= "hard_bounce"
+	SuppressionReasonComplaint 		= "complaint"
+	SuppressionReasonUnsubscribe 	= "unsubscribe"
+	SuppressionReasonManual 			= "manual"
+)
+
+var domainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// Suppressed email address or the whole domain
+type Suppression struct {
+	Address 	string 		 `json:"address" db:"address" validate:"required,lte=250"`
+	// empty category suppresses all categories
+	Category 	string 		 `json:"category,omitempty" db:"category" validate:"lte=50"`
+	Reason 		string 		 `json:"reason" db:"reason" validate:"required,oneof=hard_bounce complaint unsubscribe manual"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	// suppression without tenant is global, e.g. hard bounce reported by the mailbox provider
+	TenantID 	*uuid.UUID `json:"tenantId,omitempty" db:"tenant_id"`
+	CreatedAt time.Time  `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// Suppressions list with pagination
+type SuppressionsList struct {
+	TotalCount 		uint64 					`json:"total_count"`
+	TotalPages 		uint64 					`json:"total_pages"`
+	Page 					uint64 					`json:"page"`
+	Size 					uint64 					`json:"size"`
+	HasMore 			bool 						`json:"has_more"`
+	Suppressions 	[]*Suppression 	`json:"suppressions"`
+}
+
+// Normalize and validate suppressed address, domains are given without @
+func (s *Suppression) PrepareAndValidate(ctx context.Context) error {
+	s.Address = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(s.Address)), "@")
+	s.Category = strings.TrimSpace(strings.ToLower(s.Category))
+	if s.Reason == "" {
+		s.Reason = SuppressionReasonManual
+	}
+
+	if s.IsDomain() && !domainRegex.MatchString(s.Address) {
+		return fmt.Errorf("Validate suppression: invalid domain: %s", s.Address)
+	}
+
+	if !s.IsDomain() && !utils.ValidateEmail(s.Address) {
+		return fmt.Errorf("Validate suppression: invalid email: %s", s.Address)
+	}
+
+	return utils.ValidateStruct(ctx, s)
+}
+
+// Check if the whole domain is suppressed
+func (s *Suppression) IsDomain() bool {
+	return !strings.Contains(s.Address, "@")
+}
+
+// Get suppression addresses matching the recipient: the recipient itself and its domain
+func SuppressionAddresses(recipient string) []string {
+	recipient = strings.ToLower(recipient)
+	addresses := []string{recipient}
+	if at := strings.LastIndex(recipient, "@"); at >= 0 {
+		addresses = append(addresses, recipient[at+1:])
+	}
+	return addresses
+}
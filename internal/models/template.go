@@ -0,0 +1,134 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	htmlTemplate "html/template"
+	"io"
+	textTemplate "text/template"
+	"time"
+
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	templateMissingKey = "missingkey=error"
+)
+
+// Email template version
+type Template struct {
+	TemplateID 	uuid.UUID `json:"templateId" db:"template_id" validate:"omitempty"`
+	Version 		int 			`json:"version" db:"version"`
+	Name 				string 		`json:"name" db:"name" validate:"required,lte=250"`
+	Subject 		string 		`json:"subject" db:"subject" validate:"required,lte=250"`
+	HTMLBody 		string 		`json:"htmlBody,omitempty" db:"html_body" validate:"required_without=TextBody"`
+	TextBody 		string 		`json:"textBody,omitempty" db:"text_body" validate:"required_without=HTMLBody"`
+	CreatedAt 	time.Time `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// Rendered email template
+type RenderedTemplate struct {
+	Subject 	string
+	HTMLBody 	string
+	TextBody 	string
+}
+
+// Templates list with pagination
+type TemplatesList struct {
+	TotalCount 	uint64 			`json:"total_count"`
+	TotalPages 	uint64 			`json:"total_pages"`
+	Page 				uint64 			`json:"page"`
+	Size 				uint64 			`json:"size"`
+	HasMore 		bool 				`json:"has_more"`
+	Templates 	[]*Template `json:"templates"`
+}
+
+// Validate template fields and syntax
+func (t *Template) PrepareAndValidate(ctx context.Context) error {
+	if err := utils.ValidateStruct(ctx, t); err != nil {
+		return err
+	}
+
+	if _, err := t.parse(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Render template with variables, every variable used in template must be given
+func (t *Template) Render(variables map[string]string) (*RenderedTemplate, error) {
+	parsed, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &RenderedTemplate{}
+	if rendered.Subject, err = executeTemplate(parsed.subject, variables); err != nil {
+		return nil, errors.Wrap(err, "subject")
+	}
+
+	if parsed.html != nil {
+		if rendered.HTMLBody, err = executeTemplate(parsed.html, variables); err != nil {
+			return nil, errors.Wrap(err, "html body")
+		}
+	}
+
+	if parsed.text != nil {
+		if rendered.TextBody, err = executeTemplate(parsed.text, variables); err != nil {
+			return nil, errors.Wrap(err, "text body")
+		}
+	}
+
+	return rendered, nil
+}
+
+type templateExecutor interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+type parsedTemplate struct {
+	subject *textTemplate.Template
+	html 		*htmlTemplate.Template
+	text 		*textTemplate.Template
+}
+
+func (t *Template) parse() (*parsedTemplate, error) {
+	parsed := &parsedTemplate{}
+
+	subject, err := textTemplate.New("subject").Option(templateMissingKey).Parse(t.Subject)
+	if err != nil {
+		return nil, errors.Wrap(grpc_errors.ErrInvalidTemplate, err.Error())
+	}
+	parsed.subject = subject
+
+	if t.HTMLBody != "" {
+		html, err := htmlTemplate.New("html").Option(templateMissingKey).Parse(t.HTMLBody)
+		if err != nil {
+			return nil, errors.Wrap(grpc_errors.ErrInvalidTemplate, err.Error())
+		}
+		parsed.html = html
+	}
+
+	if t.TextBody != "" {
+		text, err := textTemplate.New("text").Option(templateMissingKey).Parse(t.TextBody)
+		if err != nil {
+			return nil, errors.Wrap(grpc_errors.ErrInvalidTemplate, err.Error())
+		}
+		parsed.text = text
+	}
+
+	return parsed, nil
+}
+
+func executeTemplate(t templateExecutor, variables map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, variables); err != nil {
+		return "", errors.Wrap(grpc_errors.ErrInvalidTemplate, err.Error())
+	}
+	return buf.String(), nil
+}
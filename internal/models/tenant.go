@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Tenant sending with its own provider credentials and default sender
+type Tenant struct {
+	TenantID 	uuid.UUID 			`json:"tenantId" db:"tenant_id" validate:"omitempty"`
+	Name 			string 					`json:"name" db:"name" validate:"required,lte=250"`
+	From 			string 					`json:"from" db:"from" validate:"required,email"`
+	// tenant without providers is sent through the configured ones
+	Providers TenantProviders `json:"providers,omitempty" db:"providers" validate:"omitempty,dive"`
+	Disabled 	bool 						`json:"disabled" db:"disabled"`
+	// zero quota falls back to the configured one
+	DailyQuota 		int64 			`json:"dailyQuota,omitempty" db:"daily_quota" validate:"gte=0"`
+	MonthlyQuota 	int64 			`json:"monthlyQuota,omitempty" db:"monthly_quota" validate:"gte=0"`
+	// status webhook of the tenant emails, events are signed with the webhook secret
+	WebhookURL 		string 			`json:"webhookUrl,omitempty" db:"webhook_url" validate:"omitempty,http_url,lte=2000"`
+	WebhookSecret string 			`json:"webhookSecret,omitempty" db:"webhook_secret" validate:"lte=250"`
+	CreatedAt time.Time 			`json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt time.Time 			`json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// Mail provider credentials of the tenant, fields match config.SmtpProvider except the webhook key
+type TenantProvider struct {
+	Name 			string `json:"name" validate:"required,lte=50"`
+	Backend 	string `json:"backend,omitempty" validate:"omitempty,oneof=smtp sendgrid mailgun"`
+	Host 			string `json:"host,omitempty" validate:"lte=250"`
+	Port 			int 	 `json:"port,omitempty" validate:"gte=0,lte=65535"`
+	User 			string `json:"user,omitempty"`
+	Password 	string `json:"password,omitempty"`
+	APIKey 		string `json:"apiKey,omitempty"`
+	BaseURL 	string `json:"baseUrl,omitempty" validate:"omitempty,url"`
+	Domain 		string `json:"domain,omitempty" validate:"required_if=Backend mailgun"`
+	From 			string `json:"from,omitempty" validate:"omitempty,email"`
+	// SendGrid webhook verification key or Mailgun webhook signing key of the provider account
+	WebhookKey string `json:"webhookKey,omitempty"`
+	Weight 		int 	 `json:"weight,omitempty" validate:"gte=0"`
+	Priority 	int 	 `json:"priority,omitempty" validate:"gte=0"`
+}
+
+// Tenant providers stored as JSON
+type TenantProviders []*TenantProvider
+
+// Tenants list with pagination
+type TenantsList struct {
+	TotalCount 	uint64 		`json:"total_count"`
+	TotalPages 	uint64 		`json:"total_pages"`
+	Page 				uint64 		`json:"page"`
+	Size 				uint64 		`json:"size"`
+	HasMore 		bool 			`json:"has_more"`
+	Tenants 		[]*Tenant `json:"tenants"`
+}
+
+// Normalize and validate tenant, provider names must be unique
+func (t *Tenant) PrepareAndValidate(ctx context.Context) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.From = strings.TrimSpace(strings.ToLower(t.From))
+	t.WebhookURL = strings.TrimSpace(t.WebhookURL)
+
+	names := make(map[string]struct{}, len(t.Providers))
+	for _, p := range t.Providers {
+		if p == nil {
+			return fmt.Errorf("Validate providers: empty provider")
+		}
+		p.Name = strings.TrimSpace(p.Name)
+		p.Backend = strings.TrimSpace(strings.ToLower(p.Backend))
+		if p.Weight == 0 {
+			p.Weight = 1
+		}
+		if (p.Backend == "" || p.Backend == "smtp") && p.Host == "" {
+			return fmt.Errorf("Validate providers: host of SMTP provider %s is required", p.Name)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("Validate providers: duplicate provider %s", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+
+	return utils.ValidateStruct(ctx, t)
+}
+
+// Keep stored secrets of the providers and the webhook which are updated without them
+func (t *Tenant) KeepSecrets(stored *Tenant) {
+	if t.WebhookSecret == "" {
+		t.WebhookSecret = stored.WebhookSecret
+	}
+
+	byName := make(map[string]*TenantProvider, len(stored.Providers))
+	for _, p := range stored.Providers {
+		byName[p.Name] = p
+	}
+
+	for _, p := range t.Providers {
+		s, ok := byName[p.Name]
+		if !ok {
+			continue
+		}
+		if p.Password == "" {
+			p.Password = s.Password
+		}
+		if p.APIKey == "" {
+			p.APIKey = s.APIKey
+		}
+		if p.WebhookKey == "" {
+			p.WebhookKey = s.WebhookKey
+		}
+	}
+}
+
+// Value of the providers column
+func (p TenantProviders) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan providers column
+func (p *TenantProviders) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	case nil:
+		*p = TenantProviders{}
+		return nil
+	default:
+		return errors.Errorf("TenantProviders.Scan: unsupported type %T", src)
+	}
+}
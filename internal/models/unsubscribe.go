@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signed unsubscribe token payload, token without tenant unsubscribes from all tenants
+type UnsubscribeToken struct {
+	Email 		string 			`json:"e"`
+	Category 	string 			`json:"c,omitempty"`
+	TenantID 	*uuid.UUID 	`json:"t,omitempty"`
+	ExpiresAt int64 			`json:"x"`
+}
+
+// Check if token is expired
+func (t *UnsubscribeToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt > 0 && now.Unix() > t.ExpiresAt
+}
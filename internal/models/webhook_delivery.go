@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Status webhook event types
+const (
+	WebhookEventSent 		= "email.sent"
+	WebhookEventFailed 	= "email.failed"
+	WebhookEventBounced = "email.bounced"
+	WebhookEventOpened 	= "email.opened"
+)
+
+// Status webhook delivery statuses
+const (
+	WebhookDeliveryPending 		= "pending"
+	WebhookDeliveryDelivered 	= "delivered"
+	WebhookDeliveryFailed 		= "failed"
+)
+
+// Event posted to the status webhook of the email
+type WebhookEvent struct {
+	// id of the delivery, repeated deliveries of the event keep it
+	EventID 	uuid.UUID `json:"id"`
+	Type 			string 		`json:"type"`
+	EmailID 	uuid.UUID `json:"emailId"`
+	Status 		string 		`json:"status,omitempty"`
+	Recipient string 		`json:"recipient,omitempty"`
+	Reason 		string 		`json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Status webhook event type of the email status, statuses without event type are not posted.
+// Failed emails are retried, email.failed is posted once the email is parked instead.
+func GetWebhookEventType(status string) string {
+	switch status {
+	case EmailStatusSent:
+		return WebhookEventSent
+	case EmailStatusBounced:
+		return WebhookEventBounced
+	}
+	return ""
+}
+
+// Status webhook event in the outbox, posted until it is delivered or runs out of attempts
+type WebhookDelivery struct {
+	DeliveryID 		uuid.UUID 	`json:"deliveryId" db:"delivery_id"`
+	EmailID 			uuid.UUID 	`json:"emailId" db:"email_id"`
+	TenantID 			*uuid.UUID 	`json:"tenantId,omitempty" db:"tenant_id"`
+	Event 				string 			`json:"event" db:"event"`
+	URL 					string 			`json:"url" db:"url"`
+	Payload 			[]byte 			`json:"-" db:"payload"`
+	Status 				string 			`json:"status" db:"status"`
+	Attempts 			int 				`json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time 	`json:"nextAttemptAt" db:"next_attempt_at"`
+	// response code and error of the last attempt
+	ResponseCode 	int 				`json:"responseCode,omitempty" db:"response_code"`
+	LastError 		string 			`json:"lastError,omitempty" db:"last_error"`
+	DeliveredAt 	*time.Time 	`json:"deliveredAt,omitempty" db:"delivered_at"`
+	CreatedAt 		time.Time 	`json:"createdAt" db:"created_at"`
+	UpdatedAt 		time.Time 	`json:"updatedAt" db:"updated_at"`
+}
+
+// Outbox delivery of the event, the event gets its id
+func NewWebhookDelivery(event *WebhookEvent) (*WebhookDelivery, error) {
+	event.EventID = uuid.New()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	return &WebhookDelivery{
+		DeliveryID: event.EventID,
+		EmailID: 		event.EmailID,
+		Event: 			event.Type,
+		Payload: 		payload,
+	}, nil
+}
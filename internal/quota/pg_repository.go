@@ -0,0 +1,15 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository.go -package mock
+
+package quota
+
+import (
+	"context"
+	"rmq_service/internal/models"
+)
+
+// Quotas repository interface
+type QuotasRepository interface {
+	ConsumeQuotas(ctx context.Context, usages []*models.QuotaUsage, recipients int64) error
+	ReleaseQuotas(ctx context.Context, usages []*models.QuotaUsage, recipients int64) error
+	FindQuotaUsages(context.Context, []*models.QuotaUsage) error
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	dateLayout = "2006-01-02"
+)
+
+// Quotas Repository
+type QuotasRepository struct {
+	db *sqlx.DB
+}
+
+// Quotas repository constructor
+func NewQuotasRepository(db *sqlx.DB) *QuotasRepository {
+	return &QuotasRepository{db: db}
+}
+
+// Add recipients to every usage in one transaction, nothing is added when any limit would be exceeded.
+// Used counts are set to the usages.
+func (r *QuotasRepository) ConsumeQuotas(ctx context.Context, usages []*models.QuotaUsage, recipients int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasRepository.ConsumeQuotas")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	for _, u := range usages {
+		var limit sql.NullInt64
+		if u.IsLimited() {
+			limit = sql.NullInt64{Int64: u.Limit, Valid: true}
+		}
+
+		if err := tx.QueryRowContext(
+			ctx,
+			consumeQuotaQuery,
+			u.Scope,
+			u.Key,
+			u.Period,
+			periodStartDate(u),
+			recipients,
+			limit,
+		).Scan(&u.Used); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(grpc_errors.ErrQuotaExceeded, "%s %s %s quota of %d recipients", u.Scope, u.Key, u.Period, u.Limit)
+			}
+			return errors.Wrap(err, "tx.QueryRowContext")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Return recipients to every usage
+func (r *QuotasRepository) ReleaseQuotas(ctx context.Context, usages []*models.QuotaUsage, recipients int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasRepository.ReleaseQuotas")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	for _, u := range usages {
+		if _, err := tx.ExecContext(ctx, releaseQuotaQuery, u.Scope, u.Key, u.Period, periodStartDate(u), recipients); err != nil {
+			return errors.Wrap(err, "tx.ExecContext")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "tx.Commit")
+}
+
+// Set used counts to the usages, usage without stored row is not used
+func (r *QuotasRepository) FindQuotaUsages(ctx context.Context, usages []*models.QuotaUsage) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasRepository.FindQuotaUsages")
+	defer span.Finish()
+
+	for _, u := range usages {
+		if err := r.db.QueryRowContext(ctx, findQuotaUsageQuery, u.Scope, u.Key, u.Period, periodStartDate(u)).Scan(&u.Used); err != nil {
+			return errors.Wrap(err, "db.QueryRowContext")
+		}
+	}
+
+	return nil
+}
+
+// Period start is passed as date string, so it does not depend on the session time zone
+func periodStartDate(u *models.QuotaUsage) string {
+	return u.PeriodStart.Format(dateLayout)
+}
@@ -0,0 +1,16 @@
+package repository
+
+const (
+	// nothing is inserted or updated when usage would exceed the limit, null limit is unlimited
+	consumeQuotaQuery = `INSERT INTO email_usage (scope, key, period, period_start, used) 
+	SELECT $1, $2, $3, $4::DATE, $5::BIGINT WHERE $6::BIGINT IS NULL OR $5 <= $6::BIGINT
+	ON CONFLICT (scope, key, period, period_start) DO UPDATE SET used = email_usage.used + EXCLUDED.used, updated_at = NOW()
+	WHERE $6::BIGINT IS NULL OR email_usage.used + EXCLUDED.used <= $6::BIGINT
+	RETURNING used`
+
+	releaseQuotaQuery = `UPDATE email_usage SET used = GREATEST(used - $5, 0), updated_at = NOW() 
+	WHERE scope = $1 AND key = $2 AND period = $3 AND period_start = $4`
+
+	findQuotaUsageQuery = `SELECT COALESCE(
+		(SELECT used FROM email_usage WHERE scope = $1 AND key = $2 AND period = $3 AND period_start = $4), 0)`
+)
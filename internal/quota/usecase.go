@@ -0,0 +1,15 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package quota
+
+import (
+	"context"
+	"rmq_service/internal/models"
+)
+
+// Quotas useCase interface
+type QuotasUseCase interface {
+	Consume(ctx context.Context, sender string, recipients int) error
+	Release(ctx context.Context, sender string, recipients int) error
+	GetUsage(ctx context.Context, sender string) ([]*models.QuotaUsage, error)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/internal/quota"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/logger"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+)
+
+// Quotas usecase struct
+type QuotasUseCase struct {
+	quotasRepo 	quota.QuotasRepository
+	cfg 				*config.Config
+	logger 			logger.Logger
+}
+
+// QuotasUseCase constructor
+func NewQuotasUseCase(quotasRepo quota.QuotasRepository, cfg *config.Config, logger logger.Logger) *QuotasUseCase {
+	return &QuotasUseCase{quotasRepo: quotasRepo, cfg: cfg, logger: logger}
+}
+
+// Take recipients from the daily and monthly quotas of the request tenant and the sender,
+// nothing is taken when any quota is exceeded
+func (u *QuotasUseCase) Consume(ctx context.Context, sender string, recipients int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasUseCase.Consume")
+	defer span.Finish()
+
+	if recipients <= 0 {
+		return nil
+	}
+
+	if err := u.quotasRepo.ConsumeQuotas(ctx, u.usages(ctx, sender, time.Now()), int64(recipients)); err != nil {
+		return errors.Wrap(err, "quotasRepo.ConsumeQuotas")
+	}
+
+	span.LogFields(log.String("sender", sender), log.Int("recipients", recipients))
+	return nil
+}
+
+// Return recipients of the email which was not accepted after all
+func (u *QuotasUseCase) Release(ctx context.Context, sender string, recipients int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasUseCase.Release")
+	defer span.Finish()
+
+	if recipients <= 0 {
+		return nil
+	}
+
+	return u.quotasRepo.ReleaseQuotas(ctx, u.usages(ctx, sender, time.Now()), int64(recipients))
+}
+
+// Get current daily and monthly usage of the request tenant and the sender
+func (u *QuotasUseCase) GetUsage(ctx context.Context, sender string) ([]*models.QuotaUsage, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "QuotasUseCase.GetUsage")
+	defer span.Finish()
+
+	usages := u.usages(ctx, sender, time.Now())
+	if err := u.quotasRepo.FindQuotaUsages(ctx, usages); err != nil {
+		return nil, errors.Wrap(err, "quotasRepo.FindQuotaUsages")
+	}
+
+	return usages, nil
+}
+
+// Usages of the current periods in the order they are locked
+func (u *QuotasUseCase) usages(ctx context.Context, sender string, now time.Time) []*models.QuotaUsage {
+	tenantKey := models.DefaultTenantQuotaKey
+	daily, monthly := u.cfg.Quotas.TenantDaily, u.cfg.Quotas.TenantMonthly
+	if t, ok := tenant.FromContext(ctx); ok {
+		tenantKey = t.TenantID.String()
+		if t.DailyQuota > 0 {
+			daily = t.DailyQuota
+		}
+		if t.MonthlyQuota > 0 {
+			monthly = t.MonthlyQuota
+		}
+	}
+
+	senderKey := models.SenderQuotaKey(tenantKey, sender)
+	return []*models.QuotaUsage{
+		models.NewQuotaUsage(models.QuotaScopeTenant, tenantKey, models.QuotaPeriodDay, daily, now),
+		models.NewQuotaUsage(models.QuotaScopeTenant, tenantKey, models.QuotaPeriodMonth, monthly, now),
+		models.NewQuotaUsage(models.QuotaScopeSender, senderKey, models.QuotaPeriodDay, u.cfg.Quotas.SenderDaily, now),
+		models.NewQuotaUsage(models.QuotaScopeSender, senderKey, models.QuotaPeriodMonth, u.cfg.Quotas.SenderMonthly, now),
+	}
+}
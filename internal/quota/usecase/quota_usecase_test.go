@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+
+	"github.com/google/uuid"
+)
+
+func TestUsagesSenderKeyedByTenant(t *testing.T) {
+	u := NewQuotasUseCase(nil, &config.Config{Quotas: config.Quotas{SenderDaily: 10, SenderMonthly: 100}}, nil)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tenantA := &models.Tenant{TenantID: uuid.New()}
+	tenantB := &models.Tenant{TenantID: uuid.New()}
+
+	senderKey := func(ctx context.Context) string {
+		for _, usage := range u.usages(ctx, "Sender@Example.com", now) {
+			if usage.Scope == models.QuotaScopeSender {
+				return usage.Key
+			}
+		}
+		t.Fatal("no sender usage")
+		return ""
+	}
+
+	keyA := senderKey(tenant.WithTenant(context.Background(), tenantA))
+	keyB := senderKey(tenant.WithTenant(context.Background(), tenantB))
+	keyDefault := senderKey(context.Background())
+
+	if keyA != tenantA.TenantID.String()+"/sender@example.com" {
+		t.Fatalf("sender key %s, want tenant and lower case sender", keyA)
+	}
+	if keyA == keyB || keyA == keyDefault || keyB == keyDefault {
+		t.Fatalf("sender keys %s, %s, %s are shared across tenants", keyA, keyB, keyDefault)
+	}
+	if keyDefault != models.DefaultTenantQuotaKey+"/sender@example.com" {
+		t.Fatalf("default tenant sender key %s", keyDefault)
+	}
+}
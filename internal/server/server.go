@@ -3,19 +3,44 @@ package server
 import (
 	"context"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"rmq_service/config"
+	"rmq_service/internal/email"
 	"rmq_service/internal/email/mailer"
 	"rmq_service/pkg/logger"
 	"syscall"
 	"time"
 
+	"rmq_service/internal/bounce"
+	bounceUseCase "rmq_service/internal/bounce/usecase"
 	"rmq_service/internal/email/delivery/rabbitmq"
+	"rmq_service/internal/email/ratelimit"
 	emailService "rmq_service/internal/email/proto"
 	"rmq_service/internal/email/repository"
+	"rmq_service/internal/email/scheduler"
 	"rmq_service/internal/email/usecase"
 	"rmq_service/internal/interceptors"
+	templateRepository "rmq_service/internal/template/repository"
+	templateUseCase "rmq_service/internal/template/usecase"
+	quotaRepository "rmq_service/internal/quota/repository"
+	quotaUseCase "rmq_service/internal/quota/usecase"
+	suppressionRepository "rmq_service/internal/suppression/repository"
+	suppressionUseCase "rmq_service/internal/suppression/usecase"
+	tenantRepository "rmq_service/internal/tenant/repository"
+	tenantUseCase "rmq_service/internal/tenant/usecase"
+	"rmq_service/internal/unsubscribe"
+	unsubscribeHttp "rmq_service/internal/unsubscribe/delivery/http"
+	"rmq_service/internal/tracking"
+	trackingHttp "rmq_service/internal/tracking/delivery/http"
+	"rmq_service/internal/webhook"
+	webhookHttp "rmq_service/internal/webhook/delivery/http"
+	"rmq_service/internal/webhook/dispatcher"
+	webhookRepository "rmq_service/internal/webhook/repository"
+	webhookUseCase "rmq_service/internal/webhook/usecase"
+	"rmq_service/pkg/dkim"
+	"rmq_service/pkg/secrets"
 	"rmq_service/pkg/metrics"
 
 	mailGrpc "rmq_service/internal/email/delivery/grpc"
@@ -24,8 +49,10 @@ import (
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	"github.com/minio/minio-go/v7"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/streadway/amqp"
 	"google.golang.org/grpc"
@@ -36,8 +63,10 @@ import (
 // Server
 type Server struct {
 	db					*sqlx.DB
-	mailDialer	*gomail.Dialer
+	mailDialers	map[string]*gomail.Dialer
 	amqpConn		*amqp.Connection
+	minioClient *minio.Client
+	redisClient *redis.Client
 	logger 			logger.Logger
 	cfg 				*config.Config
 }
@@ -47,13 +76,17 @@ func NewEmailServer(
 	amqpConn *amqp.Connection,
 	logger logger.Logger,
 	cfg *config.Config,
-	mailDialer *gomail.Dialer,
-	db *sqlx.DB) *Server {
+	mailDialers map[string]*gomail.Dialer,
+	db *sqlx.DB,
+	minioClient *minio.Client,
+	redisClient *redis.Client) *Server {
 		return &Server{
 			db: db,
 			amqpConn: amqpConn,
+			minioClient: minioClient,
+			redisClient: redisClient,
 			logger: logger,
-			mailDialer: mailDialer,
+			mailDialers: mailDialers,
 			cfg:	cfg,
 		}
 }
@@ -78,12 +111,62 @@ func (s *Server) Run() error {
 	defer emailsPublisher.CloseChan()
 	s.logger.Info("Emails Publisher initialized")
 
-	im := interceptors.NewInterceptorManager(s.logger, s.cfg, metric)
+	secretsCipher, err := secrets.NewCipher(s.cfg.Tenants.SecretsKey)
+	if err != nil {
+		return err
+	}
+	tenantsRepository := tenantRepository.NewTenantsRepository(s.db, secretsCipher)
+	tenantsUseCase := tenantUseCase.NewTenantsUseCase(tenantsRepository, s.logger)
+	quotasRepository := quotaRepository.NewQuotasRepository(s.db)
+	quotasUseCase := quotaUseCase.NewQuotasUseCase(quotasRepository, s.cfg, s.logger)
 
-	emailRepository := repository.NewEmailsRepository(s.db)
-	mailDialier := mailer.NewMailer(s.mailDialer)
-	emailUseCase := usecase.NewEmailUseCase(mailDialier, emailRepository, s.logger, s.cfg, emailsPublisher)
-	emailAmqpConsumer := rabbitmq.NewImagesConsumer(s.amqpConn, s.logger, emailUseCase)
+	webhooksRepository := webhookRepository.NewWebhooksRepository(s.db)
+	webhooksUseCase := webhookUseCase.NewWebhooksUseCase(webhooksRepository, tenantsUseCase, s.cfg, s.logger)
+
+	im := interceptors.NewInterceptorManager(s.logger, s.cfg, metric, tenantsUseCase)
+
+	emailRepository := repository.NewEmailsRepository(s.db, s.cfg.Emails.IdempotencyWindow)
+	attachmentsRepository := repository.NewAttachmentsAWSRepository(s.minioClient, s.cfg.AWS.AttachmentsBucket)
+	templatesRepository := templateRepository.NewTemplatesRepository(s.db)
+	templatesUseCase := templateUseCase.NewTemplatesUseCase(templatesRepository, s.logger)
+	suppressionsRepository := suppressionRepository.NewSuppressionsRepository(s.db)
+	suppressionsUseCase := suppressionUseCase.NewSuppressionsUseCase(suppressionsRepository, s.logger)
+	unsubscribeTokens := unsubscribe.NewTokens(s.cfg.Unsubscribe)
+	trackingTokens := tracking.NewTokens(s.cfg.Tracking)
+	returnPath := bounce.NewReturnPath(s.cfg.Bounces.ReturnPath)
+	webhookParsers, err := webhook.NewParsers(s.cfg.Webhooks)
+	if err != nil {
+		return err
+	}
+	dkimSigner, err := dkim.NewSigner(s.cfg.DKIM)
+	if err != nil {
+		return err
+	}
+	apiClient := mailer.NewAPIClient()
+	rateLimiter := ratelimit.NewRedisLimiter(s.redisClient, s.cfg.RateLimits)
+	routingMailer, err := s.newRoutingMailer(apiClient, unsubscribeTokens, dkimSigner, returnPath, rateLimiter)
+	if err != nil {
+		return err
+	}
+	mailDialier := mailer.NewTenantMailer(routingMailer, s.cfg.SmtpPool, apiClient, unsubscribeTokens, dkimSigner, returnPath, rateLimiter)
+	defer mailDialier.Close()
+	emailUseCase := usecase.NewEmailUseCase(
+		mailDialier,
+		emailRepository,
+		s.logger,
+		s.cfg,
+		emailsPublisher,
+		templatesUseCase,
+		attachmentsRepository,
+		suppressionsUseCase,
+		tenantsUseCase,
+		quotasUseCase,
+		trackingTokens,
+		webhooksUseCase,
+	)
+	emailAmqpConsumer := rabbitmq.NewImagesConsumer(s.amqpConn, s.cfg, s.logger, emailUseCase)
+	bouncesUseCase := bounceUseCase.NewBouncesUseCase(emailRepository, emailUseCase, returnPath, s.logger)
+	bouncesAmqpConsumer := rabbitmq.NewBouncesConsumer(s.amqpConn, s.cfg, s.logger, bouncesUseCase)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	
@@ -92,7 +175,20 @@ func (s *Server) Run() error {
 
 	go func() {
 		if err := router.Start(s.cfg.Metrics.URL); err != nil {
-			s.logger.Errorf("router.Start metrics: %v", err)
+			s.logger.Errorf("router.Start: %v", err)
+			cancel()
+		}
+	}()
+
+	// public routes are exposed to the internet, so they never share the metrics listener
+	publicRouter := echo.New()
+	unsubscribeHttp.NewUnsubscribeHandlers(unsubscribeTokens, suppressionsUseCase, s.logger).MapRoutes(publicRouter)
+	trackingHttp.NewTrackingHandlers(trackingTokens, emailUseCase, s.logger).MapRoutes(publicRouter)
+	webhookHttp.NewWebhookHandlers(webhookParsers, emailUseCase, tenantsUseCase, s.cfg.Webhooks, s.logger).MapRoutes(publicRouter)
+
+	go func() {
+		if err := publicRouter.Start(s.cfg.Server.PublicPort); err != nil {
+			s.logger.Errorf("publicRouter.Start: %v", err)
 			cancel()
 		}
 	}()
@@ -112,6 +208,31 @@ func (s *Server) Run() error {
 		}
 	}()
 
+	if s.cfg.Bounces.Queue != "" {
+		go func() {
+			err := bouncesAmqpConsumer.StartConsumer(
+				s.cfg.Bounces.WorkerPoolSize,
+				s.cfg.Bounces.Exchange,
+				s.cfg.Bounces.Queue,
+				s.cfg.Bounces.RoutingKey,
+				s.cfg.Bounces.ConsumerTag,
+			)
+
+			if err != nil {
+				s.logger.Errorf("bouncesAmqpConsumer.StartConsumer: %v", err)
+				cancel()
+			}
+		}()
+	}
+
+	go s.reloadDKIMKeys(ctx, dkimSigner)
+
+	emailsScheduler := scheduler.NewEmailsScheduler(emailUseCase, s.logger, s.cfg.Emails.SchedulerInterval)
+	go emailsScheduler.Run(ctx)
+
+	webhooksDispatcher := dispatcher.NewWebhooksDispatcher(webhooksUseCase, s.logger, s.cfg.StatusWebhooks.Interval)
+	go webhooksDispatcher.Run(ctx)
+
 	l, err := net.Listen("tcp", s.cfg.Server.Port)
 	if err != nil {
 		return err
@@ -129,10 +250,21 @@ func (s *Server) Run() error {
 			grpc_ctxtags.UnaryServerInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 			grpcrecovery.UnaryServerInterceptor(),
+			im.Tenant,
 		),
 	)
 
-	emailGrpcMicroservice := mailGrpc.NewEmailMicroservice(s.cfg, s.logger, emailUseCase)
+	emailGrpcMicroservice := mailGrpc.NewEmailMicroservice(
+		s.cfg,
+		s.logger,
+		emailUseCase,
+		templatesUseCase,
+		suppressionsUseCase,
+		tenantsUseCase,
+		quotasUseCase,
+		bouncesUseCase,
+		webhooksUseCase,
+	)
 	emailService.RegisterEmailServiceServer(server, emailGrpcMicroservice)
 	grpc_prometheus.Register(server)
 
@@ -155,8 +287,65 @@ func (s *Server) Run() error {
 		s.logger.Errorf("Metrics router.Shutdown: %v", err)
 	}
 
+	if err := publicRouter.Shutdown(ctx); err != nil {
+		s.logger.Errorf("Public router.Shutdown: %v", err)
+	}
+
 	server.GracefulStop()
 	s.logger.Info("Server Exited Properly")
 
 	return nil
 }
+
+// Reload DKIM domains and keys of the config file on SIGHUP
+func (s *Server) reloadDKIMKeys(ctx context.Context, dkimSigner *dkim.Signer) {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reload:
+			cfg, err := config.GetConfig(config.GetConfigPath(os.Getenv("config")))
+			if err != nil {
+				s.logger.Errorf("config.GetConfig: %v", err)
+				continue
+			}
+			if err := dkimSigner.Reload(cfg.DKIM.Keys); err != nil {
+				s.logger.Errorf("dkimSigner.Reload: %v", err)
+				continue
+			}
+			s.logger.Info("DKIM keys reloaded")
+		}
+	}
+}
+
+// Routing mailer across configured providers
+func (s *Server) newRoutingMailer(
+	apiClient *http.Client,
+	unsubscribeTokens *unsubscribe.Tokens,
+	dkimSigner *dkim.Signer,
+	returnPath *bounce.ReturnPath,
+	limiter email.RateLimiter,
+) (*mailer.RoutingMailer, error) {
+	smtpProviders := s.cfg.GetSmtpProviders()
+	providers := make([]*mailer.Provider, 0, len(smtpProviders))
+	for _, p := range smtpProviders {
+		providerMailer, err := mailer.NewProviderMailer(p, s.mailDialers[p.Name], s.cfg.SmtpPool, apiClient, unsubscribeTokens, dkimSigner, returnPath)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, &mailer.Provider{
+			Name: 		p.Name,
+			Account: 	p.Name,
+			From: 		p.From,
+			Weight: 	p.Weight,
+			Priority: p.Priority,
+			Mailer: 	providerMailer,
+		})
+	}
+	return mailer.NewRoutingMailer(providers, limiter), nil
+}
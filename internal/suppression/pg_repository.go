@@ -0,0 +1,19 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository.go -package mock
+
+package suppression
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Suppressions repository interface, suppressions are scoped by tenant
+type SuppressionsRepository interface {
+	UpsertSuppressions(context.Context, []*models.Suppression) (int, error)
+	DeleteSuppression(ctx context.Context, tenantID *uuid.UUID, address, category string) error
+	FindSuppressions(ctx context.Context, tenantID *uuid.UUID, search string, query *utils.PaginationQuery) (*models.SuppressionsList, error)
+	FindActiveSuppressions(ctx context.Context, tenantID *uuid.UUID, addresses []string, category string) ([]*models.Suppression, error)
+}
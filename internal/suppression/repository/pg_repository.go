@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Suppressions Repository
+type SuppressionsRepository struct {
+	db *sqlx.DB
+}
+
+// Suppressions repository constructor
+func NewSuppressionsRepository(db *sqlx.DB) *SuppressionsRepository {
+	return &SuppressionsRepository{db: db}
+}
+
+// Create or replace suppressions of their tenants in one transaction
+func (r *SuppressionsRepository) UpsertSuppressions(ctx context.Context, suppressions []*models.Suppression) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsRepository.UpsertSuppressions")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "db.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PreparexContext(ctx, upsertSuppressionQuery)
+	if err != nil {
+		return 0, errors.Wrap(err, "tx.PreparexContext")
+	}
+	defer stmt.Close()
+
+	for _, s := range suppressions {
+		if err := stmt.QueryRowxContext(ctx, s.Address, s.Category, s.Reason, s.ExpiresAt, s.TenantID).Scan(&s.CreatedAt); err != nil {
+			return 0, errors.Wrap(err, "stmt.QueryRowxContext")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "tx.Commit")
+	}
+
+	return len(suppressions), nil
+}
+
+// Delete tenant suppression of the address in category
+func (r *SuppressionsRepository) DeleteSuppression(ctx context.Context, tenantID *uuid.UUID, address, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsRepository.DeleteSuppression")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, deleteSuppressionQuery, address, category, tenantID)
+	if err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "result.RowsAffected")
+	}
+
+	if rowsAffected == 0 {
+		return grpc_errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// Find tenant suppressions by address substring
+func (r *SuppressionsRepository) FindSuppressions(
+	ctx context.Context,
+	tenantID *uuid.UUID,
+	search string,
+	query *utils.PaginationQuery,
+) (*models.SuppressionsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsRepository.FindSuppressions")
+	defer span.Finish()
+
+	var totalCount uint64
+	if err := r.db.GetContext(ctx, &totalCount, totalSuppressionsCountQuery, tenantID, search); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+
+	if totalCount == 0 {
+		return &models.SuppressionsList{Suppressions: []*models.Suppression{}}, nil
+	}
+
+	suppressions := make([]*models.Suppression, 0, query.GetSize())
+	if err := r.db.SelectContext(
+		ctx,
+		&suppressions,
+		findSuppressionsQuery,
+		tenantID,
+		search,
+		query.GetOffset(),
+		query.GetLimit(),
+	); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return &models.SuppressionsList{
+		TotalCount: 	totalCount,
+		TotalPages: 	utils.GetTotalPages(totalCount, query.GetSize()),
+		Page: 				query.Page,
+		Size: 				query.Size,
+		HasMore: 			utils.GetHasMore(query.GetPage(), totalCount, query.GetSize()),
+		Suppressions: suppressions,
+	}, nil
+}
+
+// Find not expired suppressions of the given addresses in category, suppressions of all categories
+// and global suppressions included
+func (r *SuppressionsRepository) FindActiveSuppressions(
+	ctx context.Context,
+	tenantID *uuid.UUID,
+	addresses []string,
+	category string,
+) ([]*models.Suppression, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsRepository.FindActiveSuppressions")
+	defer span.Finish()
+
+	suppressions := make([]*models.Suppression, 0)
+	if err := r.db.SelectContext(ctx, &suppressions, findActiveSuppressionsQuery, addresses, category, tenantID); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return suppressions, nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+const (
+	upsertSuppressionQuery = `INSERT INTO suppressions (address, category, reason, expires_at, tenant_id) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (address, category, COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000')) 
+	DO UPDATE SET reason = EXCLUDED.reason, expires_at = EXCLUDED.expires_at 
+	RETURNING created_at`
+
+	deleteSuppressionQuery = `DELETE FROM suppressions WHERE address = $1 AND category = $2 AND tenant_id IS NOT DISTINCT FROM $3`
+
+	totalSuppressionsCountQuery = `SELECT COUNT(address) FROM suppressions 
+	WHERE tenant_id IS NOT DISTINCT FROM $1 AND address ILIKE '%' || $2 || '%'`
+
+	findSuppressionsQuery = `SELECT address, category, reason, expires_at, tenant_id, created_at FROM suppressions 
+	WHERE tenant_id IS NOT DISTINCT FROM $1 AND address ILIKE '%' || $2 || '%' 
+	ORDER BY created_at DESC, address OFFSET $3 LIMIT $4`
+
+	// global suppressions apply to every tenant
+	findActiveSuppressionsQuery = `SELECT address, category, reason, expires_at, tenant_id, created_at FROM suppressions 
+	WHERE address = ANY($1) AND category IN ('', $2) AND (tenant_id IS NULL OR tenant_id = $3) 
+	AND (expires_at IS NULL OR expires_at > NOW())`
+)
@@ -0,0 +1,18 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package suppression
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+)
+
+// Suppressions useCase interface
+type SuppressionsUseCase interface {
+	AddSuppression(context.Context, *models.Suppression) (*models.Suppression, error)
+	ImportSuppressions(context.Context, []*models.Suppression) (int, error)
+	RemoveSuppression(ctx context.Context, address, category string) error
+	ListSuppressions(ctx context.Context, search string, query *utils.PaginationQuery) (*models.SuppressionsList, error)
+	FindSuppressed(ctx context.Context, recipients []string, category string) (map[string]*models.Suppression, error)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/internal/suppression"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/logger"
+	"rmq_service/pkg/utils"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Suppressions usecase struct
+type SuppressionsUseCase struct {
+	suppressionsRepo 	suppression.SuppressionsRepository
+	logger 						logger.Logger
+}
+
+// SuppressionsUseCase constructor
+func NewSuppressionsUseCase(suppressionsRepo suppression.SuppressionsRepository, logger logger.Logger) *SuppressionsUseCase {
+	return &SuppressionsUseCase{suppressionsRepo: suppressionsRepo, logger: logger}
+}
+
+// Add suppression, existing suppression of the address is replaced
+func (u *SuppressionsUseCase) AddSuppression(ctx context.Context, s *models.Suppression) (*models.Suppression, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsUseCase.AddSuppression")
+	defer span.Finish()
+
+	scopeSuppression(ctx, s)
+	if err := s.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	if _, err := u.suppressionsRepo.UpsertSuppressions(ctx, []*models.Suppression{s}); err != nil {
+		return nil, errors.Wrap(err, "suppressionsRepo.UpsertSuppressions")
+	}
+
+	return s, nil
+}
+
+// Import suppressions, nothing is imported when any of them is invalid
+func (u *SuppressionsUseCase) ImportSuppressions(ctx context.Context, suppressions []*models.Suppression) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsUseCase.ImportSuppressions")
+	defer span.Finish()
+
+	for _, s := range suppressions {
+		scopeSuppression(ctx, s)
+		if err := s.PrepareAndValidate(ctx); err != nil {
+			return 0, errors.Wrap(err, "PrepareAndValidate")
+		}
+	}
+
+	return u.suppressionsRepo.UpsertSuppressions(ctx, suppressions)
+}
+
+// Remove suppression of the address in category, global suppressions are removed only without tenant
+func (u *SuppressionsUseCase) RemoveSuppression(ctx context.Context, address, category string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsUseCase.RemoveSuppression")
+	defer span.Finish()
+
+	s := &models.Suppression{Address: address, Category: category}
+	if err := s.PrepareAndValidate(ctx); err != nil {
+		return errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	return u.suppressionsRepo.DeleteSuppression(ctx, tenant.IDFromContext(ctx), s.Address, s.Category)
+}
+
+// List suppressions of the request tenant by address substring
+func (u *SuppressionsUseCase) ListSuppressions(
+	ctx context.Context,
+	search string,
+	query *utils.PaginationQuery,
+) (*models.SuppressionsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsUseCase.ListSuppressions")
+	defer span.Finish()
+
+	return u.suppressionsRepo.FindSuppressions(ctx, tenant.IDFromContext(ctx), search, query)
+}
+
+// Find recipients suppressed in category for the request tenant,
+// the recipient is suppressed by its own address or by its domain
+func (u *SuppressionsUseCase) FindSuppressed(
+	ctx context.Context,
+	recipients []string,
+	category string,
+) (map[string]*models.Suppression, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SuppressionsUseCase.FindSuppressed")
+	defer span.Finish()
+
+	suppressed := make(map[string]*models.Suppression)
+	if len(recipients) == 0 {
+		return suppressed, nil
+	}
+
+	addresses := make([]string, 0, len(recipients)*2)
+	for _, recipient := range recipients {
+		addresses = append(addresses, models.SuppressionAddresses(recipient)...)
+	}
+
+	suppressions, err := u.suppressionsRepo.FindActiveSuppressions(ctx, tenant.IDFromContext(ctx), addresses, category)
+	if err != nil {
+		return nil, errors.Wrap(err, "suppressionsRepo.FindActiveSuppressions")
+	}
+
+	byAddress := make(map[string]*models.Suppression, len(suppressions))
+	for _, s := range suppressions {
+		byAddress[s.Address] = s
+	}
+
+	for _, recipient := range recipients {
+		for _, address := range models.SuppressionAddresses(recipient) {
+			if s, ok := byAddress[address]; ok {
+				suppressed[recipient] = s
+				break
+			}
+		}
+	}
+
+	return suppressed, nil
+}
+
+// Suppression belongs to the request tenant, requests without tenant keep the given scope
+func scopeSuppression(ctx context.Context, s *models.Suppression) {
+	if tenantID := tenant.IDFromContext(ctx); tenantID != nil {
+		s.TenantID = tenantID
+	}
+}
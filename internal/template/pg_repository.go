@@ -0,0 +1,20 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository.go -package mock
+
+package template
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Templates repository interface, templates are scoped by tenant
+type TemplatesRepository interface {
+	CreateTemplate(ctx context.Context, tenantID *uuid.UUID, t *models.Template) (*models.Template, error)
+	CreateTemplateVersion(ctx context.Context, tenantID *uuid.UUID, t *models.Template) (*models.Template, error)
+	FindTemplate(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID, version int) (*models.Template, error)
+	FindTemplates(ctx context.Context, tenantID *uuid.UUID, query *utils.PaginationQuery) (*models.TemplatesList, error)
+	DeleteTemplate(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) error
+}
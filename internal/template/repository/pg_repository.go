@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Templates Repository
+type TemplatesRepository struct {
+	db *sqlx.DB
+}
+
+// Templates repository constructor
+func NewTemplatesRepository(db *sqlx.DB) *TemplatesRepository {
+	return &TemplatesRepository{db: db}
+}
+
+// Create first version of the tenant template
+func (r *TemplatesRepository) CreateTemplate(ctx context.Context, tenantID *uuid.UUID, t *models.Template) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesRepository.CreateTemplate")
+	defer span.Finish()
+
+	created := &models.Template{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createTemplateQuery,
+		t.Name,
+		t.Subject,
+		t.HTMLBody,
+		t.TextBody,
+		tenantID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	return created, nil
+}
+
+// Create next version of existing tenant template
+func (r *TemplatesRepository) CreateTemplateVersion(ctx context.Context, tenantID *uuid.UUID, t *models.Template) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesRepository.CreateTemplateVersion")
+	defer span.Finish()
+
+	created := &models.Template{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createTemplateVersionQuery,
+		t.TemplateID,
+		t.Subject,
+		t.HTMLBody,
+		t.TextBody,
+		tenantID,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	return created, nil
+}
+
+// Find tenant template version, latest version is returned when version is 0
+func (r *TemplatesRepository) FindTemplate(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID, version int) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesRepository.FindTemplate")
+	defer span.Finish()
+
+	t := &models.Template{}
+	if version == 0 {
+		if err := r.db.GetContext(ctx, t, findLatestTemplateQuery, id, tenantID); err != nil {
+			return nil, errors.Wrap(err, "db.GetContext")
+		}
+		return t, nil
+	}
+
+	if err := r.db.GetContext(ctx, t, findTemplateVersionQuery, id, tenantID, version); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+	return t, nil
+}
+
+// Find latest versions of the tenant templates
+func (r *TemplatesRepository) FindTemplates(
+	ctx context.Context,
+	tenantID *uuid.UUID,
+	query *utils.PaginationQuery,
+) (*models.TemplatesList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesRepository.FindTemplates")
+	defer span.Finish()
+
+	var totalCount uint64
+	if err := r.db.GetContext(ctx, &totalCount, totalTemplatesCountQuery, tenantID); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+
+	if totalCount == 0 {
+		return &models.TemplatesList{Templates: []*models.Template{}}, nil
+	}
+
+	templates := make([]*models.Template, 0, query.GetSize())
+	if err := r.db.SelectContext(ctx, &templates, findTemplatesQuery, tenantID, query.GetOffset(), query.GetLimit()); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return &models.TemplatesList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, query.GetSize()),
+		Page: 			query.Page,
+		Size: 			query.Size,
+		HasMore: 		utils.GetHasMore(query.GetPage(), totalCount, query.GetSize()),
+		Templates: 	templates,
+	}, nil
+}
+
+// Delete all versions of the tenant template
+func (r *TemplatesRepository) DeleteTemplate(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesRepository.DeleteTemplate")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, deleteTemplateQuery, id, tenantID)
+	if err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "result.RowsAffected")
+	}
+
+	if rowsAffected == 0 {
+		return grpc_errors.ErrNotFound
+	}
+
+	return nil
+}
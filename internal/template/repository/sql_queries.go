@@ -0,0 +1,26 @@
+package repository
+
+const (
+	createTemplateQuery = `INSERT INTO templates (version, name, subject, html_body, text_body, tenant_id) 
+	VALUES (1, $1, $2, $3, $4, $5) RETURNING template_id, version, name, subject, html_body, text_body, created_at`
+
+	createTemplateVersionQuery = `INSERT INTO templates (template_id, version, name, subject, html_body, text_body, tenant_id)
+	SELECT t.template_id, t.version + 1, t.name, $2, $3, $4, t.tenant_id FROM templates t 
+	WHERE t.template_id = $1 AND t.tenant_id IS NOT DISTINCT FROM $5 ORDER BY t.version DESC LIMIT 1
+	RETURNING template_id, version, name, subject, html_body, text_body, created_at`
+
+	findLatestTemplateQuery = `SELECT template_id, version, name, subject, html_body, text_body, created_at 
+	FROM templates WHERE template_id = $1 AND tenant_id IS NOT DISTINCT FROM $2 ORDER BY version DESC LIMIT 1`
+
+	findTemplateVersionQuery = `SELECT template_id, version, name, subject, html_body, text_body, created_at 
+	FROM templates WHERE template_id = $1 AND tenant_id IS NOT DISTINCT FROM $2 AND version = $3`
+
+	totalTemplatesCountQuery = `SELECT COUNT(DISTINCT template_id) FROM templates WHERE tenant_id IS NOT DISTINCT FROM $1`
+
+	findTemplatesQuery = `SELECT template_id, version, name, subject, html_body, text_body, created_at FROM (
+		SELECT DISTINCT ON (template_id) template_id, version, name, subject, html_body, text_body, created_at 
+		FROM templates WHERE tenant_id IS NOT DISTINCT FROM $1 ORDER BY template_id, version DESC
+	) t ORDER BY name, template_id OFFSET $2 LIMIT $3`
+
+	deleteTemplateQuery = `DELETE FROM templates WHERE template_id = $1 AND tenant_id IS NOT DISTINCT FROM $2`
+)
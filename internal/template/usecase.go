@@ -0,0 +1,21 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package template
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Templates useCase interface
+type TemplatesUseCase interface {
+	CreateTemplate(context.Context, *models.Template) (*models.Template, error)
+	UpdateTemplate(context.Context, *models.Template) (*models.Template, error)
+	GetTemplate(ctx context.Context, id uuid.UUID, version int) (*models.Template, error)
+	ListTemplates(context.Context, *utils.PaginationQuery) (*models.TemplatesList, error)
+	DeleteTemplate(context.Context, uuid.UUID) error
+	Render(ctx context.Context, id uuid.UUID, version int, variables map[string]string) (*models.RenderedTemplate, error)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/internal/template"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/logger"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Templates usecase struct
+type TemplatesUseCase struct {
+	templatesRepo template.TemplatesRepository
+	logger 				logger.Logger
+}
+
+// TemplatesUseCase constructor
+func NewTemplatesUseCase(templatesRepo template.TemplatesRepository, logger logger.Logger) *TemplatesUseCase {
+	return &TemplatesUseCase{templatesRepo: templatesRepo, logger: logger}
+}
+
+// Create template of the request tenant
+func (u *TemplatesUseCase) CreateTemplate(ctx context.Context, t *models.Template) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.CreateTemplate")
+	defer span.Finish()
+
+	if err := t.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	return u.templatesRepo.CreateTemplate(ctx, tenant.IDFromContext(ctx), t)
+}
+
+// Update template, every update creates a new version
+func (u *TemplatesUseCase) UpdateTemplate(ctx context.Context, t *models.Template) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.UpdateTemplate")
+	defer span.Finish()
+
+	latest, err := u.templatesRepo.FindTemplate(ctx, tenant.IDFromContext(ctx), t.TemplateID, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "templatesRepo.FindTemplate")
+	}
+
+	t.Name = latest.Name
+	if err := t.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	return u.templatesRepo.CreateTemplateVersion(ctx, tenant.IDFromContext(ctx), t)
+}
+
+// Get template version, latest version is returned when version is 0
+func (u *TemplatesUseCase) GetTemplate(ctx context.Context, id uuid.UUID, version int) (*models.Template, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.GetTemplate")
+	defer span.Finish()
+
+	return u.templatesRepo.FindTemplate(ctx, tenant.IDFromContext(ctx), id, version)
+}
+
+// List latest versions of the request tenant templates
+func (u *TemplatesUseCase) ListTemplates(
+	ctx context.Context,
+	query *utils.PaginationQuery,
+) (*models.TemplatesList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.ListTemplates")
+	defer span.Finish()
+
+	return u.templatesRepo.FindTemplates(ctx, tenant.IDFromContext(ctx), query)
+}
+
+// Delete template with all versions
+func (u *TemplatesUseCase) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.DeleteTemplate")
+	defer span.Finish()
+
+	return u.templatesRepo.DeleteTemplate(ctx, tenant.IDFromContext(ctx), id)
+}
+
+// Render template version with variables
+func (u *TemplatesUseCase) Render(
+	ctx context.Context,
+	id uuid.UUID,
+	version int,
+	variables map[string]string,
+) (*models.RenderedTemplate, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TemplatesUseCase.Render")
+	defer span.Finish()
+
+	t, err := u.templatesRepo.FindTemplate(ctx, tenant.IDFromContext(ctx), id, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "templatesRepo.FindTemplate")
+	}
+
+	rendered, err := t.Render(variables)
+	if err != nil {
+		return nil, errors.Wrap(err, "Template.Render")
+	}
+
+	return rendered, nil
+}
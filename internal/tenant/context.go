@@ -0,0 +1,39 @@
+package tenant
+
+import (
+	"context"
+	"rmq_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type tenantCtxKey struct{}
+
+// Context of the request made by the tenant
+func WithTenant(ctx context.Context, t *models.Tenant) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, t)
+}
+
+// Tenant of the request, requests without API key have no tenant
+func FromContext(ctx context.Context) (*models.Tenant, bool) {
+	t, ok := ctx.Value(tenantCtxKey{}).(*models.Tenant)
+	return t, ok && t != nil
+}
+
+// Tenant id of the request, nil for requests without tenant
+func IDFromContext(ctx context.Context) *uuid.UUID {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	id := t.TenantID
+	return &id
+}
+
+// Default sender of the request tenant, fallback is used for requests without tenant
+func SenderFromContext(ctx context.Context, fallback string) string {
+	if t, ok := FromContext(ctx); ok {
+		return t.From
+	}
+	return fallback
+}
@@ -0,0 +1,21 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository.go -package mock
+
+package tenant
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Tenants repository interface
+type TenantsRepository interface {
+	CreateTenant(ctx context.Context, tenant *models.Tenant, apiKeyHash string) (*models.Tenant, error)
+	UpdateTenant(context.Context, *models.Tenant) (*models.Tenant, error)
+	UpdateTenantAPIKey(ctx context.Context, id uuid.UUID, apiKeyHash string) error
+	FindTenantById(context.Context, uuid.UUID) (*models.Tenant, error)
+	FindTenantByAPIKey(ctx context.Context, apiKeyHash string) (*models.Tenant, error)
+	FindTenants(context.Context, *utils.PaginationQuery) (*models.TenantsList, error)
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/secrets"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Tenants Repository, provider secrets are stored encrypted
+type TenantsRepository struct {
+	db 			*sqlx.DB
+	cipher 	*secrets.Cipher
+}
+
+// Tenants repository constructor
+func NewTenantsRepository(db *sqlx.DB, cipher *secrets.Cipher) *TenantsRepository {
+	return &TenantsRepository{db: db, cipher: cipher}
+}
+
+// Create tenant with the hash of its API key
+func (r *TenantsRepository) CreateTenant(ctx context.Context, tenant *models.Tenant, apiKeyHash string) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.CreateTenant")
+	defer span.Finish()
+
+	providers, err := r.encryptProviders(tenant.Providers)
+	if err != nil {
+		return nil, errors.Wrap(err, "TenantsRepository.encryptProviders")
+	}
+
+	created := &models.Tenant{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createTenantQuery,
+		tenant.Name,
+		tenant.From,
+		providers,
+		tenant.DailyQuota,
+		tenant.MonthlyQuota,
+		tenant.WebhookURL,
+		tenant.WebhookSecret,
+		apiKeyHash,
+	).StructScan(created); err != nil {
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	return created, r.decryptProviders(created)
+}
+
+// Update tenant name, sender, providers, disabled flag, quotas and webhook
+func (r *TenantsRepository) UpdateTenant(ctx context.Context, tenant *models.Tenant) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.UpdateTenant")
+	defer span.Finish()
+
+	providers, err := r.encryptProviders(tenant.Providers)
+	if err != nil {
+		return nil, errors.Wrap(err, "TenantsRepository.encryptProviders")
+	}
+
+	updated := &models.Tenant{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		updateTenantQuery,
+		tenant.TenantID,
+		tenant.Name,
+		tenant.From,
+		providers,
+		tenant.Disabled,
+		tenant.DailyQuota,
+		tenant.MonthlyQuota,
+		tenant.WebhookURL,
+		tenant.WebhookSecret,
+	).StructScan(updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, grpc_errors.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "db.QueryRowxContext")
+	}
+
+	return updated, r.decryptProviders(updated)
+}
+
+// Replace hash of the tenant API key
+func (r *TenantsRepository) UpdateTenantAPIKey(ctx context.Context, id uuid.UUID, apiKeyHash string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.UpdateTenantAPIKey")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, updateTenantAPIKeyQuery, id, apiKeyHash)
+	if err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "result.RowsAffected")
+	}
+
+	if rowsAffected == 0 {
+		return grpc_errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// Find tenant by id
+func (r *TenantsRepository) FindTenantById(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.FindTenantById")
+	defer span.Finish()
+
+	tenant := &models.Tenant{}
+	if err := r.db.GetContext(ctx, tenant, findTenantByIdQuery, id); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+
+	return tenant, r.decryptProviders(tenant)
+}
+
+// Find tenant by hash of its API key
+func (r *TenantsRepository) FindTenantByAPIKey(ctx context.Context, apiKeyHash string) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.FindTenantByAPIKey")
+	defer span.Finish()
+
+	tenant := &models.Tenant{}
+	if err := r.db.GetContext(ctx, tenant, findTenantByAPIKeyQuery, apiKeyHash); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+
+	return tenant, r.decryptProviders(tenant)
+}
+
+// Find tenants
+func (r *TenantsRepository) FindTenants(ctx context.Context, query *utils.PaginationQuery) (*models.TenantsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsRepository.FindTenants")
+	defer span.Finish()
+
+	var totalCount uint64
+	if err := r.db.GetContext(ctx, &totalCount, totalTenantsCountQuery); err != nil {
+		return nil, errors.Wrap(err, "db.GetContext")
+	}
+
+	if totalCount == 0 {
+		return &models.TenantsList{Tenants: []*models.Tenant{}}, nil
+	}
+
+	tenants := make([]*models.Tenant, 0, query.GetSize())
+	if err := r.db.SelectContext(ctx, &tenants, findTenantsQuery, query.GetOffset(), query.GetLimit()); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	for _, t := range tenants {
+		if err := r.decryptProviders(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.TenantsList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, query.GetSize()),
+		Page: 			query.Page,
+		Size: 			query.Size,
+		HasMore: 		utils.GetHasMore(query.GetPage(), totalCount, query.GetSize()),
+		Tenants: 		tenants,
+	}, nil
+}
+
+// Copy of the providers with encrypted passwords, API keys and webhook keys
+func (r *TenantsRepository) encryptProviders(providers models.TenantProviders) (models.TenantProviders, error) {
+	encrypted := make(models.TenantProviders, 0, len(providers))
+	for _, p := range providers {
+		e := *p
+
+		var err error
+		if e.Password, err = r.cipher.Encrypt(p.Password); err != nil {
+			return nil, errors.Wrapf(err, "password of provider %s", p.Name)
+		}
+		if e.APIKey, err = r.cipher.Encrypt(p.APIKey); err != nil {
+			return nil, errors.Wrapf(err, "API key of provider %s", p.Name)
+		}
+		if e.WebhookKey, err = r.cipher.Encrypt(p.WebhookKey); err != nil {
+			return nil, errors.Wrapf(err, "webhook key of provider %s", p.Name)
+		}
+		encrypted = append(encrypted, &e)
+	}
+	return encrypted, nil
+}
+
+// Decrypt passwords, API keys and webhook keys of the stored tenant providers
+func (r *TenantsRepository) decryptProviders(tenant *models.Tenant) error {
+	for _, p := range tenant.Providers {
+		var err error
+		if p.Password, err = r.cipher.Decrypt(p.Password); err != nil {
+			return errors.Wrapf(err, "password of tenant %v provider %s", tenant.TenantID, p.Name)
+		}
+		if p.APIKey, err = r.cipher.Decrypt(p.APIKey); err != nil {
+			return errors.Wrapf(err, "API key of tenant %v provider %s", tenant.TenantID, p.Name)
+		}
+		if p.WebhookKey, err = r.cipher.Decrypt(p.WebhookKey); err != nil {
+			return errors.Wrapf(err, "webhook key of tenant %v provider %s", tenant.TenantID, p.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+const (
+	tenantColumns = `tenant_id, name, "from", providers, disabled, daily_quota, monthly_quota, 
+	webhook_url, webhook_secret, created_at, updated_at`
+
+	createTenantQuery = `INSERT INTO tenants (name, "from", providers, daily_quota, monthly_quota, webhook_url, webhook_secret, api_key_hash) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + tenantColumns
+
+	updateTenantQuery = `UPDATE tenants SET name = $2, "from" = $3, providers = $4, disabled = $5, 
+	daily_quota = $6, monthly_quota = $7, webhook_url = $8, webhook_secret = $9, updated_at = NOW() 
+	WHERE tenant_id = $1 RETURNING ` + tenantColumns
+
+	updateTenantAPIKeyQuery = `UPDATE tenants SET api_key_hash = $2, updated_at = NOW() WHERE tenant_id = $1`
+
+	findTenantByIdQuery = `SELECT ` + tenantColumns + ` FROM tenants WHERE tenant_id = $1`
+
+	findTenantByAPIKeyQuery = `SELECT ` + tenantColumns + ` FROM tenants WHERE api_key_hash = $1`
+
+	totalTenantsCountQuery = `SELECT COUNT(tenant_id) FROM tenants`
+
+	findTenantsQuery = `SELECT ` + tenantColumns + ` FROM tenants ORDER BY created_at, tenant_id OFFSET $1 LIMIT $2`
+)
@@ -0,0 +1,21 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package tenant
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Tenants useCase interface
+type TenantsUseCase interface {
+	CreateTenant(context.Context, *models.Tenant) (*models.Tenant, string, error)
+	UpdateTenant(context.Context, *models.Tenant) (*models.Tenant, error)
+	GetTenant(context.Context, uuid.UUID) (*models.Tenant, error)
+	ListTenants(context.Context, *utils.PaginationQuery) (*models.TenantsList, error)
+	RotateTenantAPIKey(context.Context, uuid.UUID) (string, error)
+	Authenticate(ctx context.Context, apiKey string) (*models.Tenant, error)
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+	"rmq_service/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	apiKeyBytes = 32
+)
+
+// Tenants usecase struct
+type TenantsUseCase struct {
+	tenantsRepo tenant.TenantsRepository
+	logger 			logger.Logger
+}
+
+// TenantsUseCase constructor
+func NewTenantsUseCase(tenantsRepo tenant.TenantsRepository, logger logger.Logger) *TenantsUseCase {
+	return &TenantsUseCase{tenantsRepo: tenantsRepo, logger: logger}
+}
+
+// Create tenant, its API key is returned only once and only its hash is stored.
+// Webhook secret is generated unless it is given, the tenant verifies its status webhooks with it.
+func (u *TenantsUseCase) CreateTenant(ctx context.Context, t *models.Tenant) (*models.Tenant, string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.CreateTenant")
+	defer span.Finish()
+
+	if err := t.PrepareAndValidate(ctx); err != nil {
+		return nil, "", errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generateAPIKey")
+	}
+
+	if t.WebhookSecret == "" {
+		if t.WebhookSecret, err = generateAPIKey(); err != nil {
+			return nil, "", errors.Wrap(err, "generateAPIKey")
+		}
+	}
+
+	created, err := u.tenantsRepo.CreateTenant(ctx, t, hashAPIKey(apiKey))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "tenantsRepo.CreateTenant")
+	}
+
+	u.logger.Infof("Tenant created: %v", created.TenantID)
+	return created, apiKey, nil
+}
+
+// Update tenant, provider secrets left empty keep their stored values
+func (u *TenantsUseCase) UpdateTenant(ctx context.Context, t *models.Tenant) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.UpdateTenant")
+	defer span.Finish()
+
+	if err := t.PrepareAndValidate(ctx); err != nil {
+		return nil, errors.Wrap(err, "PrepareAndValidate")
+	}
+
+	stored, err := u.tenantsRepo.FindTenantById(ctx, t.TenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "tenantsRepo.FindTenantById")
+	}
+	t.KeepSecrets(stored)
+
+	return u.tenantsRepo.UpdateTenant(ctx, t)
+}
+
+// Get tenant by id
+func (u *TenantsUseCase) GetTenant(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.GetTenant")
+	defer span.Finish()
+
+	return u.tenantsRepo.FindTenantById(ctx, id)
+}
+
+// List tenants
+func (u *TenantsUseCase) ListTenants(ctx context.Context, query *utils.PaginationQuery) (*models.TenantsList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.ListTenants")
+	defer span.Finish()
+
+	return u.tenantsRepo.FindTenants(ctx, query)
+}
+
+// Replace tenant API key, the previous key stops working at once
+func (u *TenantsUseCase) RotateTenantAPIKey(ctx context.Context, id uuid.UUID) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.RotateTenantAPIKey")
+	defer span.Finish()
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return "", errors.Wrap(err, "generateAPIKey")
+	}
+
+	if err := u.tenantsRepo.UpdateTenantAPIKey(ctx, id, hashAPIKey(apiKey)); err != nil {
+		return "", errors.Wrap(err, "tenantsRepo.UpdateTenantAPIKey")
+	}
+
+	u.logger.Infof("Tenant API key rotated: %v", id)
+	return apiKey, nil
+}
+
+// Find enabled tenant of the API key
+func (u *TenantsUseCase) Authenticate(ctx context.Context, apiKey string) (*models.Tenant, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TenantsUseCase.Authenticate")
+	defer span.Finish()
+
+	t, err := u.tenantsRepo.FindTenantByAPIKey(ctx, hashAPIKey(apiKey))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, grpc_errors.ErrInvalidAPIKey
+		}
+		return nil, errors.Wrap(err, "tenantsRepo.FindTenantByAPIKey")
+	}
+
+	if t.Disabled {
+		return nil, grpc_errors.ErrTenantDisabled
+	}
+
+	return t, nil
+}
+
+func generateAPIKey() (string, error) {
+	key := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+func hashAPIKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
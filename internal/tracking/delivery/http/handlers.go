@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tracking"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	pixelContentType = "image/gif"
+	// user agent is cut to the column size
+	maxUserAgentLen = 500
+)
+
+// Transparent 1x1 GIF
+var pixel, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7")
+
+// Tracking HTTP handlers
+type TrackingHandlers struct {
+	tokens 	*tracking.Tokens
+	emailUC email.EmailsUseCase
+	logger 	logger.Logger
+}
+
+// Tracking HTTP handlers constructor
+func NewTrackingHandlers(tokens *tracking.Tokens, emailUC email.EmailsUseCase, logger logger.Logger) *TrackingHandlers {
+	return &TrackingHandlers{tokens: tokens, emailUC: emailUC, logger: logger}
+}
+
+// Map tracking routes
+func (h *TrackingHandlers) MapRoutes(router *echo.Echo) {
+	router.GET(tracking.OpenPath+":token", h.Open())
+	router.GET(tracking.ClickPath+":token", h.Click())
+}
+
+// Record open and serve the pixel, pixel is served for invalid tokens too, so images are never broken
+func (h *TrackingHandlers) Open() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(c.Request().Context(), "TrackingHandlers.Open")
+		defer span.Finish()
+
+		token, err := h.tokens.Verify(c.Param("token"))
+		if err != nil {
+			h.logger.Errorf("Tracking open: %v", err)
+		} else {
+			h.recordEvent(ctx, c, token, models.EmailEventOpen)
+		}
+
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store, no-cache, must-revalidate")
+		return c.Blob(http.StatusOK, pixelContentType, pixel)
+	}
+}
+
+// Record click and redirect to the signed link
+func (h *TrackingHandlers) Click() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(c.Request().Context(), "TrackingHandlers.Click")
+		defer span.Finish()
+
+		token, err := h.tokens.Verify(c.Param("token"))
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+
+		// open tokens carry no link
+		if token.URL == "" {
+			return h.errorResponse(c, grpc_errors.ErrInvalidTrackingToken)
+		}
+
+		h.recordEvent(ctx, c, token, models.EmailEventClick)
+		return c.Redirect(http.StatusFound, token.URL)
+	}
+}
+
+// Record event, failures are only logged so the recipient is never affected
+func (h *TrackingHandlers) recordEvent(ctx context.Context, c echo.Context, token *models.TrackingToken, eventType string) {
+	userAgent := c.Request().UserAgent()
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLen], "")
+	}
+
+	event := &models.EmailEvent{
+		EmailID: 		token.EmailID,
+		Type: 			eventType,
+		URL: 				token.URL,
+		UserAgent: 	userAgent,
+		IP: 				c.RealIP(),
+	}
+	if err := h.emailUC.RecordEmailEvent(ctx, event); err != nil {
+		h.logger.Errorf("emailUC.RecordEmailEvent %v: %v", token.EmailID, err)
+	}
+}
+
+func (h *TrackingHandlers) errorResponse(c echo.Context, err error) error {
+	h.logger.Errorf("Tracking %s: %v", c.Path(), err)
+	status := grpc_errors.MapGRPCErrCodeToHttpStatus(grpc_errors.ParseGRPCErrStatusCode(err))
+	return c.String(status, http.StatusText(status))
+}
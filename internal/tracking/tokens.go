@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/hmactoken"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	OpenPath 	= "/track/open/"
+	ClickPath = "/track/click/"
+)
+
+var (
+	// sanitized html has double quoted attributes
+	linkHrefRegex = regexp.MustCompile(`(?i)(<a\s[^>]*?href=")([^"]*)(")`)
+	bodyEndRegex 	= regexp.MustCompile(`(?i)</body\s*>`)
+)
+
+// Tracking tokens signer
+type Tokens struct {
+	signer 	*hmactoken.Signer
+	baseURL string
+}
+
+// Tracking tokens constructor
+func NewTokens(cfg config.Tracking) *Tokens {
+	return &Tokens{
+		signer: 	hmactoken.NewSigner(cfg.Secret),
+		baseURL: 	strings.TrimRight(cfg.BaseURL, "/"),
+	}
+}
+
+// Check if tracking URLs can be generated
+func (t *Tokens) Enabled() bool {
+	return t != nil && t.signer.Enabled() && t.baseURL != ""
+}
+
+// Rewrite http links of the html body to click tracking redirects and append open tracking pixel.
+// Body must be already sanitized, the sanitizer would strip the tracking markup otherwise.
+func (t *Tokens) TrackHTML(emailID uuid.UUID, body string) (string, error) {
+	var rewriteErr error
+	body = linkHrefRegex.ReplaceAllStringFunc(body, func(link string) string {
+		parts := linkHrefRegex.FindStringSubmatch(link)
+		target := html.UnescapeString(parts[2])
+		if !isTrackedLink(target) {
+			return link
+		}
+
+		clickURL, err := t.URL(ClickPath, &models.TrackingToken{EmailID: emailID, URL: target})
+		if err != nil {
+			rewriteErr = err
+			return link
+		}
+		return parts[1] + html.EscapeString(clickURL) + parts[3]
+	})
+	if rewriteErr != nil {
+		return "", rewriteErr
+	}
+
+	openURL, err := t.URL(OpenPath, &models.TrackingToken{EmailID: emailID})
+	if err != nil {
+		return "", err
+	}
+
+	pixel := fmt.Sprintf(`<img src="%s" width="1" height="1" alt="" style="border:0;width:1px;height:1px"/>`, html.EscapeString(openURL))
+	if loc := bodyEndRegex.FindAllStringIndex(body, -1); len(loc) > 0 {
+		end := loc[len(loc)-1][0]
+		return body[:end] + pixel + body[end:], nil
+	}
+	return body + pixel, nil
+}
+
+// Get tracking URL of the token
+func (t *Tokens) URL(path string, token *models.TrackingToken) (string, error) {
+	signed, err := t.Sign(token)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s%s", t.baseURL, path, url.PathEscape(signed)), nil
+}
+
+// Sign tracking token
+func (t *Tokens) Sign(token *models.TrackingToken) (string, error) {
+	return t.signer.Sign(token)
+}
+
+// Verify token signature
+func (t *Tokens) Verify(token string) (*models.TrackingToken, error) {
+	payload := &models.TrackingToken{}
+	if err := t.signer.Verify(token, payload); err != nil {
+		return nil, errors.Wrap(grpc_errors.ErrInvalidTrackingToken, err.Error())
+	}
+	return payload, nil
+}
+
+// Only absolute http links are redirected, mailto and anchors are kept
+func isTrackedLink(link string) bool {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
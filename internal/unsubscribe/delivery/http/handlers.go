@@ -0,0 +1,104 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+	"rmq_service/internal/models"
+	"rmq_service/internal/suppression"
+	"rmq_service/internal/unsubscribe"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/opentracing/opentracing-go"
+)
+
+var (
+	confirmPage = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html><body>
+<p>Unsubscribe {{.Email}}{{if .Category}} from {{.Category}} emails{{end}}?</p>
+<form method="POST"><button type="submit">Unsubscribe</button></form>
+</body></html>`))
+
+	unsubscribedPage = template.Must(template.New("unsubscribed").Parse(`<!DOCTYPE html>
+<html><body>
+<p>{{.Email}} is unsubscribed{{if .Category}} from {{.Category}} emails{{end}}.</p>
+</body></html>`))
+)
+
+// Unsubscribe HTTP handlers
+type UnsubscribeHandlers struct {
+	tokens 				*unsubscribe.Tokens
+	suppressionUC suppression.SuppressionsUseCase
+	logger 				logger.Logger
+}
+
+// Unsubscribe HTTP handlers constructor
+func NewUnsubscribeHandlers(
+	tokens *unsubscribe.Tokens,
+	suppressionUC suppression.SuppressionsUseCase,
+	logger logger.Logger) *UnsubscribeHandlers {
+	return &UnsubscribeHandlers{tokens: tokens, suppressionUC: suppressionUC, logger: logger}
+}
+
+// Map unsubscribe routes
+func (h *UnsubscribeHandlers) MapRoutes(router *echo.Echo) {
+	router.GET("/unsubscribe/:token", h.Confirm())
+	router.POST("/unsubscribe/:token", h.Unsubscribe())
+}
+
+// Show unsubscribe confirmation page, GET never unsubscribes,
+// so links opened by mail scanners have no effect
+func (h *UnsubscribeHandlers) Confirm() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, _ := opentracing.StartSpanFromContext(c.Request().Context(), "UnsubscribeHandlers.Confirm")
+		defer span.Finish()
+
+		token, err := h.tokens.Verify(c.Param("token"))
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+
+		return h.render(c, confirmPage, token)
+	}
+}
+
+// Unsubscribe recipient, handles RFC 8058 one-click requests and the confirmation form
+func (h *UnsubscribeHandlers) Unsubscribe() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(c.Request().Context(), "UnsubscribeHandlers.Unsubscribe")
+		defer span.Finish()
+
+		token, err := h.tokens.Verify(c.Param("token"))
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+
+		if _, err := h.suppressionUC.AddSuppression(ctx, &models.Suppression{
+			Address: 	token.Email,
+			Category: token.Category,
+			Reason: 	models.SuppressionReasonUnsubscribe,
+			TenantID: token.TenantID,
+		}); err != nil {
+			return h.errorResponse(c, err)
+		}
+
+		h.logger.Infof("Unsubscribed %s, category: %s", token.Email, token.Category)
+		return h.render(c, unsubscribedPage, token)
+	}
+}
+
+func (h *UnsubscribeHandlers) render(c echo.Context, page *template.Template, token *models.UnsubscribeToken) error {
+	var body strings.Builder
+	if err := page.Execute(&body, token); err != nil {
+		return h.errorResponse(c, err)
+	}
+	return c.HTML(http.StatusOK, body.String())
+}
+
+func (h *UnsubscribeHandlers) errorResponse(c echo.Context, err error) error {
+	h.logger.Errorf("Unsubscribe %s: %v", c.Request().Method, err)
+	status := grpc_errors.MapGRPCErrCodeToHttpStatus(grpc_errors.ParseGRPCErrStatusCode(err))
+	return c.String(status, http.StatusText(status))
+}
@@ -0,0 +1,72 @@
+package unsubscribe
+
+import (
+	"fmt"
+	"net/url"
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/hmactoken"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	unsubscribePath = "/unsubscribe/"
+)
+
+// Unsubscribe tokens signer
+type Tokens struct {
+	signer 	*hmactoken.Signer
+	baseURL string
+	ttl 		time.Duration
+}
+
+// Unsubscribe tokens constructor
+func NewTokens(cfg config.Unsubscribe) *Tokens {
+	return &Tokens{
+		signer: 	hmactoken.NewSigner(cfg.Secret),
+		baseURL: 	strings.TrimRight(cfg.BaseURL, "/"),
+		ttl: 			cfg.TokenTTL,
+	}
+}
+
+// Check if unsubscribe links can be generated
+func (t *Tokens) Enabled() bool {
+	return t != nil && t.signer.Enabled() && t.baseURL != ""
+}
+
+// Sign unsubscribe token of the tenant recipient in category
+func (t *Tokens) Sign(recipient, category string, tenantID *uuid.UUID) (string, error) {
+	payload := &models.UnsubscribeToken{Email: strings.ToLower(recipient), Category: category, TenantID: tenantID}
+	if t.ttl > 0 {
+		payload.ExpiresAt = time.Now().Add(t.ttl).Unix()
+	}
+	return t.signer.Sign(payload)
+}
+
+// Verify token signature and expiration
+func (t *Tokens) Verify(token string) (*models.UnsubscribeToken, error) {
+	payload := &models.UnsubscribeToken{}
+	if err := t.signer.Verify(token, payload); err != nil {
+		return nil, errors.Wrap(grpc_errors.ErrInvalidUnsubscribeToken, err.Error())
+	}
+
+	if payload.IsExpired(time.Now()) {
+		return nil, errors.Wrap(grpc_errors.ErrInvalidUnsubscribeToken, "token expired")
+	}
+
+	return payload, nil
+}
+
+// Get unsubscribe URL of the tenant recipient in category
+func (t *Tokens) URL(recipient, category string, tenantID *uuid.UUID) (string, error) {
+	token, err := t.Sign(recipient, category, tenantID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s%s", t.baseURL, unsubscribePath, url.PathEscape(token)), nil
+}
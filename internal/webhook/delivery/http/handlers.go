@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"rmq_service/config"
+	"rmq_service/internal/email"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/internal/webhook"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// default max size of the webhook request body
+const defaultMaxBodySize = 1 << 20
+
+var deliveryEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "emails_webhook_delivery_events_total",
+	Help: "The total number of delivery events received by provider webhooks by provider and result",
+}, []string{"provider", "result"})
+
+// Provider webhook HTTP handlers
+type WebhookHandlers struct {
+	parsers 		webhook.Parsers
+	emailUC 		email.EmailsUseCase
+	tenantsUC 	tenant.TenantsUseCase
+	cfg 				config.Webhooks
+	maxBodySize int64
+	logger 			logger.Logger
+}
+
+// Webhook HTTP handlers constructor
+func NewWebhookHandlers(
+	parsers webhook.Parsers,
+	emailUC email.EmailsUseCase,
+	tenantsUC tenant.TenantsUseCase,
+	cfg config.Webhooks,
+	logger logger.Logger,
+) *WebhookHandlers {
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+	return &WebhookHandlers{
+		parsers: 			parsers,
+		emailUC: 			emailUC,
+		tenantsUC: 		tenantsUC,
+		cfg: 					cfg,
+		maxBodySize: 	maxBodySize,
+		logger: 			logger,
+	}
+}
+
+// Map webhook routes, webhooks of the tenant provider accounts are verified with the keys of the tenant
+func (h *WebhookHandlers) MapRoutes(router *echo.Echo) {
+	router.POST(webhook.WebhookPath+":provider", h.DeliveryEvents())
+	router.POST(webhook.WebhookPath+":provider/:tenantId", h.DeliveryEvents())
+}
+
+// Verify and apply provider delivery events. Events which match no email are skipped,
+// any other failure is answered with server error, so the provider retries the whole batch.
+func (h *WebhookHandlers) DeliveryEvents() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(c.Request().Context(), "WebhookHandlers.DeliveryEvents")
+		defer span.Finish()
+
+		provider := c.Param("provider")
+		if provider != webhook.ProviderSendGrid && provider != webhook.ProviderMailgun {
+			return c.String(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, h.maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return c.String(http.StatusRequestEntityTooLarge, http.StatusText(http.StatusRequestEntityTooLarge))
+			}
+			return h.errorResponse(c, err)
+		}
+
+		parser, t, err := h.verify(ctx, provider, c.Param("tenantId"), c.Request().Header, body)
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+		// events of the tenant webhook apply to the emails of the tenant only
+		if t != nil {
+			ctx = tenant.WithTenant(ctx, t)
+		}
+
+		events, err := parser.Parse(body)
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+
+		for _, event := range events {
+			err := h.emailUC.ApplyDeliveryEvent(ctx, event)
+			switch {
+			case errors.Is(err, grpc_errors.ErrEmailNotMatched):
+				h.logger.Warnf("Skip %s %s event of %s: %v", provider, event.Type, event.Recipient, err)
+				deliveryEvents.WithLabelValues(provider, "dropped").Inc()
+			case err != nil:
+				deliveryEvents.WithLabelValues(provider, "error").Inc()
+				return h.errorResponse(c, err)
+			default:
+				deliveryEvents.WithLabelValues(provider, "applied").Inc()
+			}
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// Verify request with the configured key of the provider, or with the webhook keys of the tenant accounts
+// of the provider when the webhook belongs to the tenant. Returns parser which verified the request
+// and the tenant of the webhook, nil for the configured key.
+func (h *WebhookHandlers) verify(
+	ctx context.Context,
+	provider string,
+	tenantID string,
+	header http.Header,
+	body []byte,
+) (webhook.Parser, *models.Tenant, error) {
+	if tenantID == "" {
+		parser, ok := h.parsers[provider]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: webhook key is not configured", grpc_errors.ErrNotFound)
+		}
+		return parser, nil, parser.Verify(header, body)
+	}
+
+	id, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, nil, grpc_errors.ErrNotFound
+	}
+
+	t, err := h.tenantsUC.GetTenant(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = fmt.Errorf("%w: webhook key of the tenant is not configured", grpc_errors.ErrNotFound)
+	for _, p := range t.Providers {
+		if p.Backend != provider || p.WebhookKey == "" {
+			continue
+		}
+
+		parser, parserErr := webhook.NewParser(provider, p.WebhookKey, h.cfg.MaxEventAge)
+		if parserErr != nil {
+			h.logger.Warnf("Webhook key of tenant %v provider %s: %v", id, p.Name, parserErr)
+			continue
+		}
+		if err = parser.Verify(header, body); err == nil {
+			return parser, t, nil
+		}
+	}
+	return nil, nil, err
+}
+
+func (h *WebhookHandlers) errorResponse(c echo.Context, err error) error {
+	h.logger.Errorf("Webhook %s: %v", c.Param("provider"), err)
+	status := grpc_errors.MapGRPCErrCodeToHttpStatus(grpc_errors.ParseGRPCErrStatusCode(err))
+	return c.String(status, http.StatusText(status))
+}
@@ -0,0 +1,60 @@
+package dispatcher
+
+import (
+	"context"
+	"rmq_service/internal/webhook"
+	"rmq_service/pkg/logger"
+	"time"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+)
+
+// Status webhooks outbox dispatcher
+type WebhooksDispatcher struct {
+	webhookUC webhook.WebhooksUseCase
+	logger 		logger.Logger
+	interval 	time.Duration
+}
+
+// Status webhooks dispatcher constructor
+func NewWebhooksDispatcher(webhookUC webhook.WebhooksUseCase, logger logger.Logger, interval time.Duration) *WebhooksDispatcher {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &WebhooksDispatcher{webhookUC: webhookUC, logger: logger, interval: interval}
+}
+
+// Deliver due webhooks until context is done
+func (d *WebhooksDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Webhooks dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+// Deliver all due webhooks batch by batch, retried deliveries are due later
+func (d *WebhooksDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := d.webhookUC.DeliverDueWebhooks(ctx)
+		if err != nil {
+			d.logger.Errorf("webhookUC.DeliverDueWebhooks: %v", err)
+			return
+		}
+
+		if delivered == 0 {
+			return
+		}
+
+		d.logger.Infof("Webhook deliveries attempted: %d", delivered)
+	}
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type mailgunSignature struct {
+	Timestamp string `json:"timestamp"`
+	Token 		string `json:"token"`
+	Signature string `json:"signature"`
+}
+
+type mailgunEventData struct {
+	ID 						string `json:"id"`
+	Event 				string `json:"event"`
+	Severity 			string `json:"severity"`
+	Recipient 		string `json:"recipient"`
+	Reason 				string `json:"reason"`
+	DeliveryStatus struct {
+		Code 				int 		`json:"code"`
+		Message 		string 	`json:"message"`
+		Description string 	`json:"description"`
+	} `json:"delivery-status"`
+	Message struct {
+		Headers struct {
+			MessageID string `json:"message-id"`
+		} `json:"headers"`
+	} `json:"message"`
+	UserVariables map[string]interface{} `json:"user-variables"`
+}
+
+// Mailgun webhook request, one event per request
+type mailgunWebhook struct {
+	Signature mailgunSignature `json:"signature"`
+	EventData mailgunEventData `json:"event-data"`
+}
+
+// Parser of the Mailgun webhooks
+type MailgunParser struct {
+	signingKey 	[]byte
+	maxEventAge time.Duration
+}
+
+// Mailgun parser constructor
+func NewMailgunParser(signingKey string, maxEventAge time.Duration) *MailgunParser {
+	return &MailgunParser{signingKey: []byte(signingKey), maxEventAge: maxEventAge}
+}
+
+// Verify HMAC-SHA256 signature of the timestamp followed by the token, signature is a part of the body.
+// Requests with stale timestamp are rejected.
+func (p *MailgunParser) Verify(header http.Header, body []byte) error {
+	webhook := &mailgunWebhook{}
+	if err := json.Unmarshal(body, webhook); err != nil {
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookPayload, "json.Unmarshal: %v", err)
+	}
+
+	signature, err := hex.DecodeString(webhook.Signature.Signature)
+	if err != nil || len(signature) == 0 {
+		return errors.Wrap(grpc_errors.ErrInvalidWebhookSignature, "signature is missing")
+	}
+
+	mac := hmac.New(sha256.New, p.signingKey)
+	mac.Write([]byte(webhook.Signature.Timestamp + webhook.Signature.Token))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return grpc_errors.ErrInvalidWebhookSignature
+	}
+	return checkTimestamp(webhook.Signature.Timestamp, p.maxEventAge)
+}
+
+// Parse Mailgun event
+func (p *MailgunParser) Parse(body []byte) ([]*models.DeliveryEvent, error) {
+	webhook := &mailgunWebhook{}
+	if err := json.Unmarshal(body, webhook); err != nil {
+		return nil, errors.Wrapf(grpc_errors.ErrInvalidWebhookPayload, "json.Unmarshal: %v", err)
+	}
+	data := webhook.EventData
+
+	event := &models.DeliveryEvent{
+		MessageID: strings.Trim(data.Message.Headers.MessageID, "<>"),
+		Recipient: strings.ToLower(data.Recipient),
+		Reason: 	 mailgunReason(data),
+		ProviderEventID: data.ID,
+	}
+	if emailID, ok := data.UserVariables[models.DeliveryEventEmailIDVariable].(string); ok {
+		if id, err := uuid.Parse(emailID); err == nil {
+			event.EmailID = id
+		}
+	}
+
+	switch data.Event {
+	case "delivered":
+		event.Type = models.EmailEventDelivered
+	case "failed":
+		// temporary failures are retried by Mailgun
+		event.Type = models.EmailEventBounce
+		if data.Severity == "temporary" {
+			event.Type = models.EmailEventDeferred
+		}
+	case "complained":
+		event.Type = models.EmailEventComplaint
+		event.Reason = "abuse"
+	default:
+		return []*models.DeliveryEvent{}, nil
+	}
+	return []*models.DeliveryEvent{event}, nil
+}
+
+// SMTP code and message of the delivery status, Mailgun reason when there is no status
+func mailgunReason(data mailgunEventData) string {
+	message := data.DeliveryStatus.Message
+	if message == "" {
+		message = data.DeliveryStatus.Description
+	}
+	if data.DeliveryStatus.Code == 0 {
+		return strings.TrimSpace(message + " " + data.Reason)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", data.DeliveryStatus.Code, message))
+}
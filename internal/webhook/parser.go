@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"net/http"
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Webhook providers, named by the last segment of the webhook path
+const (
+	WebhookPath 			= "/webhooks/"
+	ProviderSendGrid 	= "sendgrid"
+	ProviderMailgun 	= "mailgun"
+
+	// signed requests older than the max age are rejected as replayed
+	defaultMaxEventAge = 5 * time.Minute
+)
+
+// Provider webhook parser, request signature is verified before the events are parsed
+type Parser interface {
+	Verify(header http.Header, body []byte) error
+	// normalize provider events, events of other types are skipped
+	Parse(body []byte) ([]*models.DeliveryEvent, error)
+}
+
+// Parsers by provider name
+type Parsers map[string]Parser
+
+// Parsers of the providers which have their verification key configured
+func NewParsers(cfg config.Webhooks) (Parsers, error) {
+	keys := map[string]string{
+		ProviderSendGrid: cfg.SendGridPublicKey,
+		ProviderMailgun: 	cfg.MailgunSigningKey,
+	}
+
+	parsers := Parsers{}
+	for provider, key := range keys {
+		if key == "" {
+			continue
+		}
+		parser, err := NewParser(provider, key, cfg.MaxEventAge)
+		if err != nil {
+			return nil, errors.Wrap(err, "NewParser")
+		}
+		parsers[provider] = parser
+	}
+	return parsers, nil
+}
+
+// Parser of the provider verifying requests with the key, e.g. the webhook key of the tenant provider account
+func NewParser(provider, key string, maxEventAge time.Duration) (Parser, error) {
+	if maxEventAge <= 0 {
+		maxEventAge = defaultMaxEventAge
+	}
+
+	switch provider {
+	case ProviderSendGrid:
+		parser, err := NewSendGridParser(key, maxEventAge)
+		if err != nil {
+			return nil, errors.Wrap(err, "NewSendGridParser")
+		}
+		return parser, nil
+	case ProviderMailgun:
+		return NewMailgunParser(key, maxEventAge), nil
+	default:
+		return nil, errors.Errorf("unknown webhook provider %s", provider)
+	}
+}
+
+// Check that the unix timestamp of the signed request is not older than the max age
+func checkTimestamp(timestamp string, maxAge time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(grpc_errors.ErrInvalidWebhookSignature, "timestamp is missing")
+	}
+
+	age := time.Since(time.Unix(unix, 0))
+	if age > maxAge || age < -maxAge {
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookSignature, "timestamp is %v old", age.Round(time.Second))
+	}
+	return nil
+}
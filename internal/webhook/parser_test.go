@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"rmq_service/pkg/grpc_errors"
+)
+
+func mailgunBody(key string, timestamp int64) []byte {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(ts + "token"))
+	return []byte(fmt.Sprintf(
+		`{"signature":{"timestamp":%q,"token":"token","signature":%q},`+
+			`"event-data":{"id":"mg-event-1","event":"delivered","recipient":"To@Example.com",`+
+			`"message":{"headers":{"message-id":"<mg-message@example.com>"}}}}`,
+		ts, hex.EncodeToString(mac.Sum(nil)),
+	))
+}
+
+func TestMailgunParser(t *testing.T) {
+	parser, err := NewParser(ProviderMailgun, "signing-key", 0)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+
+	body := mailgunBody("signing-key", time.Now().Unix())
+	if err := parser.Verify(http.Header{}, body); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	events, err := parser.Parse(body)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Parse = %v, %v", events, err)
+	}
+	if events[0].ProviderEventID != "mg-event-1" || events[0].MessageID != "mg-message@example.com" {
+		t.Errorf("event = %+v", events[0])
+	}
+
+	for name, body := range map[string][]byte{
+		"stale":     mailgunBody("signing-key", time.Now().Add(-10*time.Minute).Unix()),
+		"other key": mailgunBody("other-key", time.Now().Unix()),
+	} {
+		if err := parser.Verify(http.Header{}, body); !errors.Is(err, grpc_errors.ErrInvalidWebhookSignature) {
+			t.Errorf("Verify %s = %v, want ErrInvalidWebhookSignature", name, err)
+		}
+	}
+}
+
+func TestSendGridParser(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKIXPublicKey: %v", err)
+	}
+
+	parser, err := NewParser(ProviderSendGrid, base64.StdEncoding.EncodeToString(der), time.Minute)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+
+	body := []byte(`[{"event":"bounce","email":"to@example.com","sg_message_id":"sg-message.filter",` +
+		`"sg_event_id":"sg-event-1","status":"5.1.1","reason":"unknown user"}]`)
+	sign := func(timestamp int64) http.Header {
+		ts := strconv.FormatInt(timestamp, 10)
+		digest := sha256.Sum256(append([]byte(ts), body...))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatalf("ecdsa.SignASN1: %v", err)
+		}
+		header := http.Header{}
+		header.Set(sendGridTimestampHeader, ts)
+		header.Set(sendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+		return header
+	}
+
+	if err := parser.Verify(sign(time.Now().Unix()), body); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := parser.Verify(sign(time.Now().Add(-2*time.Minute).Unix()), body); !errors.Is(err, grpc_errors.ErrInvalidWebhookSignature) {
+		t.Errorf("Verify stale = %v, want ErrInvalidWebhookSignature", err)
+	}
+
+	events, err := parser.Parse(body)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Parse = %v, %v", events, err)
+	}
+	if events[0].ProviderEventID != "sg-event-1" || events[0].MessageID != "sg-message" {
+		t.Errorf("event = %+v", events[0])
+	}
+}
@@ -0,0 +1,19 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository.go -package mock
+
+package webhook
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status webhooks outbox repository interface
+type WebhooksRepository interface {
+	CreateWebhookDelivery(context.Context, *models.WebhookDelivery) (bool, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	UpdateWebhookDelivery(context.Context, *models.WebhookDelivery) error
+	FindWebhookDeliveries(ctx context.Context, tenantID *uuid.UUID, emailID uuid.UUID) ([]*models.WebhookDelivery, error)
+}
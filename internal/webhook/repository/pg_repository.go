@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"rmq_service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Status webhooks outbox Repository
+type WebhooksRepository struct {
+	db *sqlx.DB
+}
+
+// Webhooks repository constructor
+func NewWebhooksRepository(db *sqlx.DB) *WebhooksRepository {
+	return &WebhooksRepository{db: db}
+}
+
+// Add delivery to the outbox with the webhook url of the email, false is returned when the email has no webhook
+func (r *WebhooksRepository) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksRepository.CreateWebhookDelivery")
+	defer span.Finish()
+
+	return CreateWebhookDeliveryTx(ctx, r.db, delivery)
+}
+
+// Add delivery to the outbox inside the transaction of the caller, e.g. the email status update
+func CreateWebhookDeliveryTx(ctx context.Context, tx sqlx.ExecerContext, delivery *models.WebhookDelivery) (bool, error) {
+	result, err := tx.ExecContext(
+		ctx,
+		createWebhookDeliveryQuery,
+		delivery.DeliveryID,
+		delivery.EmailID,
+		delivery.Event,
+		delivery.Payload,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "tx.ExecContext")
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "result.RowsAffected")
+	}
+
+	return created > 0, nil
+}
+
+// Postpone due deliveries by the lease time, so other replicas skip them.
+// Deliveries which are not updated before the lease ends are claimed again.
+func (r *WebhooksRepository) ClaimDueWebhookDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*models.WebhookDelivery, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksRepository.ClaimDueWebhookDeliveries")
+	defer span.Finish()
+
+	deliveries := make([]*models.WebhookDelivery, 0, limit)
+	if err := r.db.SelectContext(
+		ctx,
+		&deliveries,
+		claimDueWebhookDeliveriesQuery,
+		limit,
+		time.Now().Add(lease),
+	); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return deliveries, nil
+}
+
+// Update delivery with the result of its attempt
+func (r *WebhooksRepository) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksRepository.UpdateWebhookDelivery")
+	defer span.Finish()
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		updateWebhookDeliveryQuery,
+		delivery.DeliveryID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.ResponseCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+	); err != nil {
+		return errors.Wrap(err, "db.ExecContext")
+	}
+
+	return nil
+}
+
+// Find deliveries of the tenant email, nil tenant id stands for the default tenant
+func (r *WebhooksRepository) FindWebhookDeliveries(
+	ctx context.Context,
+	tenantID *uuid.UUID,
+	emailID uuid.UUID,
+) ([]*models.WebhookDelivery, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksRepository.FindWebhookDeliveries")
+	defer span.Finish()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err := r.db.SelectContext(ctx, &deliveries, findWebhookDeliveriesQuery, emailID, tenantID); err != nil {
+		return nil, errors.Wrap(err, "db.SelectContext")
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+const (
+	webhookDeliveryColumns = `delivery_id, email_id, tenant_id, event, url, payload, status, attempts, next_attempt_at, 
+	response_code, last_error, delivered_at, created_at, updated_at`
+
+	// webhook url of the email overrides the tenant one, nothing is created for emails without webhook
+	createWebhookDeliveryQuery = `INSERT INTO webhook_deliveries (delivery_id, email_id, tenant_id, event, url, payload)
+	SELECT $1, e.email_id, e.tenant_id, $3, COALESCE(NULLIF(e.webhook_url, ''), t.webhook_url), $4
+	FROM emails e LEFT JOIN tenants t ON t.tenant_id = e.tenant_id
+	WHERE e.email_id = $2 AND COALESCE(NULLIF(e.webhook_url, ''), t.webhook_url, '') <> ''`
+
+	claimDueWebhookDeliveriesQuery = `UPDATE webhook_deliveries SET next_attempt_at = $2
+	WHERE delivery_id IN (
+		SELECT delivery_id FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= NOW()
+		ORDER BY next_attempt_at LIMIT $1 FOR UPDATE SKIP LOCKED
+	) RETURNING ` + webhookDeliveryColumns
+
+	updateWebhookDeliveryQuery = `UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, 
+	response_code = $5, last_error = $6, delivered_at = $7, updated_at = NOW() WHERE delivery_id = $1`
+
+	findWebhookDeliveriesQuery = `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries 
+	WHERE email_id = $1 AND tenant_id IS NOT DISTINCT FROM $2 ORDER BY created_at, delivery_id`
+)
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"rmq_service/internal/models"
+	"rmq_service/pkg/grpc_errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// SendGrid event, custom args of the message are the fields of the event
+type sendGridEvent struct {
+	Event 				string `json:"event"`
+	Email 				string `json:"email"`
+	SgMessageID 	string `json:"sg_message_id"`
+	Type 					string `json:"type"`
+	Status 				string `json:"status"`
+	Reason 				string `json:"reason"`
+	Response 			string `json:"response"`
+	SgEventID 		string `json:"sg_event_id"`
+	// custom arg of models.DeliveryEventEmailIDVariable
+	EmailID 			string `json:"email_id"`
+}
+
+// Parser of the SendGrid signed event webhook
+type SendGridParser struct {
+	publicKey 	*ecdsa.PublicKey
+	maxEventAge time.Duration
+}
+
+// SendGrid parser constructor, public key is the base64 verification key of the signed event webhook
+func NewSendGridParser(publicKey string, maxEventAge time.Duration) (*SendGridParser, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "base64.DecodeString")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "x509.ParsePKIXPublicKey")
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("unsupported key type %T", key)
+	}
+	return &SendGridParser{publicKey: ecdsaKey, maxEventAge: maxEventAge}, nil
+}
+
+// Verify ECDSA signature of the timestamp followed by the body, requests with stale timestamp are rejected
+func (p *SendGridParser) Verify(header http.Header, body []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(header.Get(sendGridSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return errors.Wrap(grpc_errors.ErrInvalidWebhookSignature, "signature is missing")
+	}
+
+	digest := sha256.Sum256(append([]byte(header.Get(sendGridTimestampHeader)), body...))
+	if !ecdsa.VerifyASN1(p.publicKey, digest[:], signature) {
+		return grpc_errors.ErrInvalidWebhookSignature
+	}
+	return checkTimestamp(header.Get(sendGridTimestampHeader), p.maxEventAge)
+}
+
+// Parse batch of SendGrid events
+func (p *SendGridParser) Parse(body []byte) ([]*models.DeliveryEvent, error) {
+	var events []*sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, errors.Wrapf(grpc_errors.ErrInvalidWebhookPayload, "json.Unmarshal: %v", err)
+	}
+
+	deliveryEvents := make([]*models.DeliveryEvent, 0, len(events))
+	for _, e := range events {
+		event := &models.DeliveryEvent{
+			// sg_message_id is the message id of the send response followed by the filter suffix
+			MessageID: strings.SplitN(e.SgMessageID, ".", 2)[0],
+			Recipient: strings.ToLower(e.Email),
+			ProviderEventID: e.SgEventID,
+		}
+		if id, err := uuid.Parse(e.EmailID); err == nil {
+			event.EmailID = id
+		}
+
+		switch e.Event {
+		case "delivered":
+			event.Type = models.EmailEventDelivered
+			event.Reason = e.Response
+		case "deferred":
+			event.Type = models.EmailEventDeferred
+			event.Reason = e.Response
+		case "bounce":
+			// blocked messages are rejected for reasons not related to the recipient, e.g. sender reputation
+			event.Type = models.EmailEventBounce
+			if e.Type == "blocked" {
+				event.Type = models.EmailEventSoftBounce
+			}
+			event.Reason = strings.TrimSpace(e.Status + " " + e.Reason)
+		case "spamreport":
+			event.Type = models.EmailEventComplaint
+			event.Reason = "abuse"
+		default:
+			continue
+		}
+		deliveryEvents = append(deliveryEvents, event)
+	}
+	return deliveryEvents, nil
+}
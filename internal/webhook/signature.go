@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers of the status webhook request
+const (
+	EventIDHeader 	= "X-Webhook-Id"
+	EventTypeHeader = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+
+	signatureVersion = "v1"
+)
+
+// Signature of the status webhook request: hex HMAC-SHA256 of the unix timestamp and the body joined with a dot,
+// prefixed with the signature version, e.g. v1=5257a869...
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"net"
+	"net/url"
+	"rmq_service/config"
+	"rmq_service/pkg/grpc_errors"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Networks of the service which are not covered by the net.IP checks, e.g. carrier-grade NAT
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+)
+
+// Plain http webhooks are allowed in Development mode only
+func RequireHTTPS(cfg *config.Config) bool {
+	return cfg.Server.Mode != "Development"
+}
+
+// Check status webhook URL: http is allowed only when https is not required,
+// host given as an IP address must be public
+func ValidateURL(rawURL string, requireHTTPS bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookURL, "url.Parse: %v", err)
+	}
+
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && !requireHTTPS:
+	default:
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookURL, "scheme %q is not allowed", u.Scheme)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return errors.Wrap(grpc_errors.ErrInvalidWebhookURL, "host is missing")
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookURL, "address %s is not public", ip)
+	}
+	return nil
+}
+
+// Check that the address is not loopback, private, link-local (including the cloud metadata address),
+// multicast, unspecified or reserved
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Dialer control rejecting connections to the addresses which are not public.
+// It runs after the host name is resolved, so names resolved to internal addresses are rejected as well.
+func DialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrap(err, "net.SplitHostPort")
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return errors.Wrapf(grpc_errors.ErrInvalidWebhookURL, "address %s is not public", host)
+	}
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"rmq_service/pkg/grpc_errors"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34": 		true,
+		"2606:4700::1111": 	true,
+		"127.0.0.1": 				false,
+		"::1": 							false,
+		"10.1.2.3": 				false,
+		"172.16.0.1": 			false,
+		"192.168.1.1": 			false,
+		"169.254.169.254": 	false,
+		"fd00:ec2::254": 		false,
+		"fe80::1": 					false,
+		"100.64.0.1": 			false,
+		"0.0.0.0": 					false,
+		"::ffff:127.0.0.1": false,
+	}
+
+	for address, public := range cases {
+		if got := IsPublicIP(net.ParseIP(address)); got != public {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", address, got, public)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	cases := []struct {
+		url 					string
+		requireHTTPS 	bool
+		valid 				bool
+	}{
+		{url: "https://hooks.example.com/email", requireHTTPS: true, valid: true},
+		{url: "http://hooks.example.com/email", requireHTTPS: false, valid: true},
+		{url: "http://hooks.example.com/email", requireHTTPS: true, valid: false},
+		{url: "https://169.254.169.254/latest/meta-data", requireHTTPS: true, valid: false},
+		{url: "http://127.0.0.1:8080/", requireHTTPS: false, valid: false},
+		{url: "https://[::1]/", requireHTTPS: true, valid: false},
+		{url: "ftp://hooks.example.com/", requireHTTPS: false, valid: false},
+		{url: "https:///path", requireHTTPS: true, valid: false},
+	}
+
+	for _, tc := range cases {
+		err := ValidateURL(tc.url, tc.requireHTTPS)
+		if tc.valid && err != nil {
+			t.Errorf("ValidateURL(%s) = %v", tc.url, err)
+		}
+		if !tc.valid && !errors.Is(err, grpc_errors.ErrInvalidWebhookURL) {
+			t.Errorf("ValidateURL(%s) = %v, want ErrInvalidWebhookURL", tc.url, err)
+		}
+	}
+}
+
+func TestDialPublicOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dialer := &net.Dialer{Timeout: time.Second, Control: DialPublicOnly}
+	client := &http.Client{Transport: &http.Transport{DialContext: dialer.DialContext}}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequestWithContext: %v", err)
+	}
+	if _, err := client.Do(req); !errors.Is(err, grpc_errors.ErrInvalidWebhookURL) {
+		t.Fatalf("client.Do = %v, want ErrInvalidWebhookURL", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:generate mockgen -source usecase.go -destination mock/usecase.go -package mock
+
+package webhook
+
+import (
+	"context"
+	"rmq_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Status webhooks useCase interface
+type WebhooksUseCase interface {
+	EnqueueEvent(ctx context.Context, event *models.WebhookEvent) error
+	DeliverDueWebhooks(ctx context.Context) (int, error)
+	GetWebhookDeliveries(ctx context.Context, emailID uuid.UUID) ([]*models.WebhookDelivery, error)
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"rmq_service/config"
+	"rmq_service/internal/models"
+	"rmq_service/internal/tenant"
+	"rmq_service/internal/webhook"
+	"rmq_service/pkg/grpc_errors"
+	"rmq_service/pkg/logger"
+	"rmq_service/pkg/mime_types"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	defaultBatchSize 			= 100
+	defaultLease 					= time.Minute
+	defaultTimeout 				= 10 * time.Second
+	defaultMaxAttempts 		= 8
+	defaultRetryBaseDelay = 30 * time.Second
+	defaultMaxRetryDelay 	= time.Hour
+	// response body is drained up to the limit, so the connection is reused
+	maxResponseBodySize 	= 64 << 10
+)
+
+var (
+	errSecretNotConfigured = errors.New("webhook secret is not configured")
+
+	webhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_status_webhook_deliveries_total",
+		Help: "The total number of status webhook delivery attempts by result",
+	}, []string{"result"})
+)
+
+// Status webhooks usecase struct
+type WebhooksUseCase struct {
+	webhooksRepo 	webhook.WebhooksRepository
+	tenantsUC 		tenant.TenantsUseCase
+	cfg 					*config.Config
+	client 				*http.Client
+	logger 				logger.Logger
+}
+
+// WebhooksUseCase constructor
+func NewWebhooksUseCase(
+	webhooksRepo webhook.WebhooksRepository,
+	tenantsUC tenant.TenantsUseCase,
+	cfg *config.Config,
+	logger logger.Logger,
+) *WebhooksUseCase {
+	timeout := cfg.StatusWebhooks.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	// webhook URLs are given by the callers, connections to the service network are rejected
+	// after the host name is resolved and proxies of the environment are not used
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout: 	 timeout,
+		KeepAlive: 30 * time.Second,
+		Control: 	 webhook.DialPublicOnly,
+	}).DialContext
+
+	return &WebhooksUseCase{
+		webhooksRepo: webhooksRepo,
+		tenantsUC: 		tenantsUC,
+		cfg: 					cfg,
+		client: &http.Client{
+			Timeout: 		timeout,
+			Transport: 	transport,
+			// redirects are not followed, POST would be turned into GET
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Add event to the outbox of the email status webhook, event of the email without webhook is dropped
+func (u *WebhooksUseCase) EnqueueEvent(ctx context.Context, event *models.WebhookEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksUseCase.EnqueueEvent")
+	defer span.Finish()
+
+	delivery, err := models.NewWebhookDelivery(event)
+	if err != nil {
+		return errors.Wrap(err, "models.NewWebhookDelivery")
+	}
+
+	created, err := u.webhooksRepo.CreateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		return errors.Wrap(err, "webhooksRepo.CreateWebhookDelivery")
+	}
+
+	span.LogFields(log.String("emailID", event.EmailID.String()), log.String("type", event.Type), log.Bool("created", created))
+	return nil
+}
+
+// Post claimed due deliveries concurrently, returns the number of claimed deliveries
+func (u *WebhooksUseCase) DeliverDueWebhooks(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksUseCase.DeliverDueWebhooks")
+	defer span.Finish()
+
+	batchSize := u.cfg.StatusWebhooks.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	lease := u.cfg.StatusWebhooks.Lease
+	if lease <= 0 {
+		lease = defaultLease
+	}
+
+	deliveries, err := u.webhooksRepo.ClaimDueWebhookDeliveries(ctx, batchSize, lease)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhooksRepo.ClaimDueWebhookDeliveries")
+	}
+
+	secrets := make(map[uuid.UUID]string)
+	wg := &sync.WaitGroup{}
+	for _, d := range deliveries {
+		secret, err := u.signingSecret(ctx, d, secrets)
+		if err != nil {
+			// delivery is claimed again when the lease ends
+			u.logger.Errorf("signingSecret of webhook delivery %v: %v", d.DeliveryID, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(d *models.WebhookDelivery) {
+			defer wg.Done()
+			u.deliver(ctx, d, secret)
+		}(d)
+	}
+	wg.Wait()
+
+	span.LogFields(log.Int("deliveries", len(deliveries)))
+	return len(deliveries), nil
+}
+
+// Get status webhook deliveries of the request tenant email
+func (u *WebhooksUseCase) GetWebhookDeliveries(ctx context.Context, emailID uuid.UUID) ([]*models.WebhookDelivery, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "WebhooksUseCase.GetWebhookDeliveries")
+	defer span.Finish()
+
+	return u.webhooksRepo.FindWebhookDeliveries(ctx, tenant.IDFromContext(ctx), emailID)
+}
+
+// Webhook secret of the delivery tenant, configured secret is used for the default tenant only,
+// deliveries of the tenant without secret fail. Tenant secrets are cached for the batch.
+func (u *WebhooksUseCase) signingSecret(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	secrets map[uuid.UUID]string,
+) (string, error) {
+	if delivery.TenantID == nil {
+		return u.cfg.StatusWebhooks.Secret, nil
+	}
+
+	secret, ok := secrets[*delivery.TenantID]
+	if !ok {
+		t, err := u.tenantsUC.GetTenant(ctx, *delivery.TenantID)
+		if err != nil {
+			return "", errors.Wrap(err, "tenantsUC.GetTenant")
+		}
+		secret = t.WebhookSecret
+		secrets[*delivery.TenantID] = secret
+	}
+	return secret, nil
+}
+
+// Post delivery and store the result of the attempt. Failed delivery is retried with doubling delay,
+// it fails for good when attempts run out, there is no secret or the URL is not allowed.
+func (u *WebhooksUseCase) deliver(ctx context.Context, delivery *models.WebhookDelivery, secret string) {
+	delivery.Attempts++
+
+	var err error
+	if secret == "" {
+		delivery.ResponseCode, err = 0, errSecretNotConfigured
+	} else {
+		delivery.ResponseCode, err = u.post(ctx, delivery, secret)
+	}
+
+	maxAttempts := u.cfg.StatusWebhooks.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	var result string
+	switch {
+	case err == nil:
+		deliveredAt := time.Now()
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+		result = delivery.Status
+	case errors.Is(err, errSecretNotConfigured), errors.Is(err, grpc_errors.ErrInvalidWebhookURL),
+		delivery.Attempts >= maxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+		result = delivery.Status
+	default:
+		delivery.NextAttemptAt = time.Now().Add(u.retryDelay(delivery.Attempts))
+		delivery.LastError = err.Error()
+		result = "retried"
+	}
+
+	if err != nil {
+		u.logger.Warnf("Webhook delivery %v of email %v, attempt %d: %v", delivery.DeliveryID, delivery.EmailID, delivery.Attempts, err)
+	}
+	webhookDeliveries.WithLabelValues(result).Inc()
+
+	if err := u.webhooksRepo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		u.logger.Errorf("webhooksRepo.UpdateWebhookDelivery %v: %v", delivery.DeliveryID, err)
+	}
+}
+
+// Post signed payload, any response except 2xx is a failure. Returns the response status code.
+func (u *WebhooksUseCase) post(ctx context.Context, delivery *models.WebhookDelivery, secret string) (int, error) {
+	if err := webhook.ValidateURL(delivery.URL, webhook.RequireHTTPS(u.cfg)); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "http.NewRequestWithContext")
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", mime_types.MIMEApplicationJSON)
+	req.Header.Set(webhook.EventIDHeader, delivery.DeliveryID.String())
+	req.Header.Set(webhook.EventTypeHeader, delivery.Event)
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, timestamp, delivery.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "client.Do")
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodySize)); err != nil {
+		u.logger.Warnf("Read webhook response body: %v", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Delay before the next attempt, doubled by every failed attempt up to the max delay
+func (u *WebhooksUseCase) retryDelay(attempts int) time.Duration {
+	baseDelay := u.cfg.StatusWebhooks.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+
+	maxDelay := u.cfg.StatusWebhooks.MaxRetryDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+
+	delay := baseDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
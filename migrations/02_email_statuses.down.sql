@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS email_status_transitions CASCADE;
+
+DROP INDEX IF EXISTS emails_status_idx;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS updated_at;
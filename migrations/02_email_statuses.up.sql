@@ -0,0 +1,20 @@
+ALTER TABLE emails
+    ADD COLUMN status        VARCHAR(32)              NOT NULL DEFAULT 'accepted',
+    ADD COLUMN status_reason VARCHAR(1000)            NOT NULL DEFAULT '',
+    ADD COLUMN updated_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+-- emails were only stored after a successful send so far
+UPDATE emails SET status = 'sent';
+
+CREATE INDEX IF NOT EXISTS emails_status_idx ON emails (status);
+
+CREATE TABLE email_status_transitions
+(
+    id         BIGSERIAL PRIMARY KEY,
+    email_id   UUID                     NOT NULL REFERENCES emails (email_id) ON DELETE CASCADE,
+    status     VARCHAR(32)              NOT NULL,
+    reason     VARCHAR(1000)            NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS email_status_transitions_email_id_idx ON email_status_transitions (email_id, created_at);
@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys
+(
+    key          VARCHAR(255) PRIMARY KEY,
+    email_id     UUID                     NOT NULL REFERENCES emails (email_id) ON DELETE CASCADE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_email_id_idx ON idempotency_keys (email_id);
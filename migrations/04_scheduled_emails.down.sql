@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS scheduled_emails CASCADE;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS send_at;
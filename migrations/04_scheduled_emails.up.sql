@@ -0,0 +1,15 @@
+ALTER TABLE emails
+    ADD COLUMN send_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE scheduled_emails
+(
+    email_id     UUID PRIMARY KEY REFERENCES emails (email_id) ON DELETE CASCADE,
+    send_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    payload      BYTEA                    NOT NULL,
+    content_type VARCHAR(250)             NOT NULL,
+    message_id   VARCHAR(255)             NOT NULL DEFAULT '',
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_emails_send_at_idx ON scheduled_emails (send_at);
@@ -0,0 +1,13 @@
+CREATE TABLE templates
+(
+    template_id UUID                     NOT NULL DEFAULT uuid_generate_v4(),
+    version     INT                      NOT NULL,
+    name        VARCHAR(250)             NOT NULL,
+    subject     VARCHAR(250)             NOT NULL,
+    html_body   TEXT                     NOT NULL DEFAULT '',
+    text_body   TEXT                     NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (template_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS templates_name_idx ON templates (name);
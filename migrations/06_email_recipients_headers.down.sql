@@ -0,0 +1,5 @@
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS cc,
+    DROP COLUMN IF EXISTS bcc,
+    DROP COLUMN IF EXISTS reply_to,
+    DROP COLUMN IF EXISTS headers;
@@ -0,0 +1,5 @@
+ALTER TABLE emails
+    ADD COLUMN cc       VARCHAR(2000) NOT NULL DEFAULT '',
+    ADD COLUMN bcc      VARCHAR(2000) NOT NULL DEFAULT '',
+    ADD COLUMN reply_to VARCHAR(250)  NOT NULL DEFAULT '',
+    ADD COLUMN headers  JSONB         NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE emails
+    ADD COLUMN text_body TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS emails_batch_id_idx;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS batch_id;
+
+DROP TABLE IF EXISTS email_batches;
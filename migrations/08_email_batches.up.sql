@@ -0,0 +1,11 @@
+CREATE TABLE email_batches
+(
+    batch_id   UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    total      INT                      NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE emails
+    ADD COLUMN batch_id UUID REFERENCES email_batches (batch_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS emails_batch_id_idx ON emails (batch_id);
@@ -0,0 +1,9 @@
+CREATE TABLE suppressions
+(
+    address    VARCHAR(250) PRIMARY KEY,
+    reason     VARCHAR(50)              NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS suppressions_expires_at_idx ON suppressions (expires_at);
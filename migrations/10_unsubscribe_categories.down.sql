@@ -0,0 +1,11 @@
+DELETE FROM suppressions WHERE category <> '';
+
+ALTER TABLE suppressions
+    DROP CONSTRAINT IF EXISTS suppressions_pkey,
+    ADD PRIMARY KEY (address);
+
+ALTER TABLE suppressions
+    DROP COLUMN IF EXISTS category;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS category;
@@ -0,0 +1,10 @@
+ALTER TABLE emails
+    ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+
+-- empty category suppresses all categories
+ALTER TABLE suppressions
+    ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+
+ALTER TABLE suppressions
+    DROP CONSTRAINT IF EXISTS suppressions_pkey,
+    ADD PRIMARY KEY (address, category);
@@ -0,0 +1,3 @@
+-- SMTP provider which sent the email
+ALTER TABLE emails
+    ADD COLUMN provider VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- message id assigned by the provider API
+ALTER TABLE emails
+    ADD COLUMN provider_message_id VARCHAR(250) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE scheduled_emails
+    DROP COLUMN IF EXISTS priority;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,5 @@
+ALTER TABLE emails
+    ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'normal';
+
+ALTER TABLE scheduled_emails
+    ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'normal';
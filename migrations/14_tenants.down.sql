@@ -0,0 +1,4 @@
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants CASCADE;
@@ -0,0 +1,17 @@
+CREATE TABLE tenants
+(
+    tenant_id    UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    name         VARCHAR(250)             NOT NULL,
+    "from"       VARCHAR(250)             NOT NULL,
+    providers    JSONB                    NOT NULL DEFAULT '[]',
+    api_key_hash VARCHAR(64)              NOT NULL UNIQUE,
+    disabled     BOOLEAN                  NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- emails without tenant belong to the default tenant
+ALTER TABLE emails
+    ADD COLUMN tenant_id UUID REFERENCES tenants (tenant_id);
+
+CREATE INDEX IF NOT EXISTS emails_tenant_id_idx ON emails (tenant_id);
@@ -0,0 +1,5 @@
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS monthly_quota,
+    DROP COLUMN IF EXISTS daily_quota;
+
+DROP TABLE IF EXISTS email_usage CASCADE;
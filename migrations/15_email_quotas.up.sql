@@ -0,0 +1,16 @@
+-- recipients accepted in the period by tenant or sender
+CREATE TABLE email_usage
+(
+    scope        VARCHAR(20)              NOT NULL,
+    key          VARCHAR(250)             NOT NULL,
+    period       VARCHAR(10)              NOT NULL,
+    period_start DATE                     NOT NULL,
+    used         BIGINT                   NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, key, period, period_start)
+);
+
+-- zero quota of the tenant falls back to the configured one
+ALTER TABLE tenants
+    ADD COLUMN daily_quota BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN monthly_quota BIGINT NOT NULL DEFAULT 0;
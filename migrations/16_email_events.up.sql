@@ -0,0 +1,12 @@
+CREATE TABLE email_events
+(
+    event_id   BIGSERIAL PRIMARY KEY,
+    email_id   UUID                     NOT NULL REFERENCES emails (email_id) ON DELETE CASCADE,
+    type       VARCHAR(20)              NOT NULL,
+    url        TEXT                     NOT NULL DEFAULT '',
+    user_agent VARCHAR(500)             NOT NULL DEFAULT '',
+    ip         VARCHAR(45)              NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS email_events_email_id_idx ON email_events (email_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS emails_provider_message_id_idx;
+
+ALTER TABLE email_events
+    DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS recipient;
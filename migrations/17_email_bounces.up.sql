@@ -0,0 +1,7 @@
+-- bounced or complaining recipient of the bounce and complaint events
+ALTER TABLE email_events
+    ADD COLUMN recipient VARCHAR(250) NOT NULL DEFAULT '',
+    ADD COLUMN reason    TEXT         NOT NULL DEFAULT '';
+
+-- bounce reports are matched to the emails by Message-ID
+CREATE INDEX IF NOT EXISTS emails_provider_message_id_idx ON emails (provider_message_id) WHERE provider_message_id <> '';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS webhook_deliveries CASCADE;
+
+ALTER TABLE emails
+    DROP COLUMN IF EXISTS webhook_url;
+
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS webhook_secret,
+    DROP COLUMN IF EXISTS webhook_url;
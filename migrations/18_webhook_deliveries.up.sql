@@ -0,0 +1,29 @@
+-- status webhook of the tenant emails, emails may override the url
+ALTER TABLE tenants
+    ADD COLUMN webhook_url    TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN webhook_secret VARCHAR(250) NOT NULL DEFAULT '';
+
+ALTER TABLE emails
+    ADD COLUMN webhook_url TEXT NOT NULL DEFAULT '';
+
+-- outbox of the status webhook events
+CREATE TABLE webhook_deliveries
+(
+    delivery_id     UUID PRIMARY KEY,
+    email_id        UUID                     NOT NULL REFERENCES emails (email_id) ON DELETE CASCADE,
+    tenant_id       UUID REFERENCES tenants (tenant_id) ON DELETE CASCADE,
+    event           VARCHAR(50)              NOT NULL,
+    url             TEXT                     NOT NULL,
+    payload         JSONB                    NOT NULL,
+    status          VARCHAR(20)              NOT NULL DEFAULT 'pending',
+    attempts        INTEGER                  NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    response_code   INTEGER                  NOT NULL DEFAULT 0,
+    last_error      TEXT                     NOT NULL DEFAULT '',
+    delivered_at    TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_email_id_idx ON webhook_deliveries (email_id, created_at);
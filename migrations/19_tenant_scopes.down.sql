@@ -0,0 +1,15 @@
+DELETE FROM suppressions WHERE tenant_id IS NOT NULL;
+
+DROP INDEX IF EXISTS suppressions_scope_idx;
+
+ALTER TABLE suppressions
+    DROP COLUMN IF EXISTS tenant_id,
+    ADD PRIMARY KEY (address, category);
+
+ALTER TABLE email_batches
+    DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS templates_tenant_id_idx;
+
+ALTER TABLE templates
+    DROP COLUMN IF EXISTS tenant_id;
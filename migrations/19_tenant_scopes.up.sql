@@ -0,0 +1,18 @@
+-- templates, batches and suppressions without tenant belong to the default tenant
+ALTER TABLE templates
+    ADD COLUMN tenant_id UUID REFERENCES tenants (tenant_id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS templates_tenant_id_idx ON templates (tenant_id);
+
+ALTER TABLE email_batches
+    ADD COLUMN tenant_id UUID REFERENCES tenants (tenant_id) ON DELETE CASCADE;
+
+-- suppressions without tenant are global, e.g. hard bounces reported by the mailbox providers
+ALTER TABLE suppressions
+    ADD COLUMN tenant_id UUID REFERENCES tenants (tenant_id) ON DELETE CASCADE;
+
+ALTER TABLE suppressions
+    DROP CONSTRAINT IF EXISTS suppressions_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS suppressions_scope_idx
+    ON suppressions (address, category, COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'));
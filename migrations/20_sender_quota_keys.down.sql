@@ -0,0 +1,4 @@
+DELETE FROM email_usage WHERE scope = 'sender' AND key LIKE '%/%';
+
+ALTER TABLE email_usage
+    ALTER COLUMN key TYPE VARCHAR(250);
@@ -0,0 +1,3 @@
+-- sender usage is keyed by the tenant and the sender, usage of the bare sender keys is not carried over
+ALTER TABLE email_usage
+    ALTER COLUMN key TYPE VARCHAR(300);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS email_events_provider_event_id_idx;
+
+ALTER TABLE email_events
+    DROP COLUMN IF EXISTS provider_event_id;
@@ -0,0 +1,6 @@
+-- provider webhook events delivered again are recorded once
+ALTER TABLE email_events
+    ADD COLUMN provider_event_id VARCHAR(250);
+
+CREATE UNIQUE INDEX IF NOT EXISTS email_events_provider_event_id_idx ON email_events (provider_event_id)
+    WHERE provider_event_id IS NOT NULL;
@@ -0,0 +1,7 @@
+DELETE FROM idempotency_keys WHERE tenant_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idempotency_keys_scope_idx;
+
+ALTER TABLE idempotency_keys
+    DROP COLUMN IF EXISTS tenant_id,
+    ADD PRIMARY KEY (key);
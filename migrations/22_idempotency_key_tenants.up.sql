@@ -0,0 +1,14 @@
+-- idempotency keys are unique within the tenant, keys without tenant belong to the default tenant
+ALTER TABLE idempotency_keys
+    ADD COLUMN tenant_id UUID REFERENCES tenants (tenant_id) ON DELETE CASCADE;
+
+UPDATE idempotency_keys
+SET tenant_id = emails.tenant_id
+FROM emails
+WHERE emails.email_id = idempotency_keys.email_id;
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_keys_scope_idx
+    ON idempotency_keys (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'), key);
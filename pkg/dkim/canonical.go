@@ -0,0 +1,85 @@
+package dkim
+
+import (
+	"bytes"
+	"strings"
+)
+
+const crlf = "\r\n"
+
+// Split message into header fields and body, header fields keep their folding
+func splitMessage(message []byte) ([]string, []byte) {
+	headerEnd := bytes.Index(message, []byte(crlf+crlf))
+	if headerEnd < 0 {
+		return splitHeaderFields(string(message)), nil
+	}
+	return splitHeaderFields(string(message[:headerEnd+len(crlf)])), message[headerEnd+2*len(crlf):]
+}
+
+// Header fields with their continuation lines, each field ends with CRLF
+func splitHeaderFields(header string) []string {
+	fields := make([]string, 0)
+	for _, line := range strings.SplitAfter(header, crlf) {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	return fields
+}
+
+// Relaxed header canonicalization, RFC 6376 section 3.4.2
+func relaxedHeader(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = strings.ReplaceAll(value, crlf, "")
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(collapseWhitespace(value)) + crlf
+}
+
+// Relaxed body canonicalization, RFC 6376 section 3.4.4
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), crlf)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, crlf) + crlf)
+}
+
+// Replace every whitespace sequence with a single space
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// Name of the header field in lower case
+func headerFieldName(field string) string {
+	name, _, _ := strings.Cut(field, ":")
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,197 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"rmq_service/config"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	signatureHeader = "DKIM-Signature"
+	foldWidth 			= 72
+)
+
+// Header fields signed by default, fields missing in the message are skipped
+var defaultSignedHeaders = []string{
+	"From", "Reply-To", "Subject", "Date", "To", "Cc", "Message-ID",
+	"MIME-Version", "Content-Type", "List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// Private key of the sending domain
+type domainKey struct {
+	selector 	string
+	algorithm string
+	signer 		crypto.Signer
+}
+
+// DKIM signer of outgoing messages with per domain keys loaded from files
+type Signer struct {
+	cfg 		config.DKIM
+	headers []string
+	mu 			sync.RWMutex
+	keys 		map[string]*domainKey
+}
+
+// Signer constructor, keys are loaded from the configured files
+func NewSigner(cfg config.DKIM) (*Signer, error) {
+	headers := cfg.Headers
+	if len(headers) == 0 {
+		headers = defaultSignedHeaders
+	}
+
+	s := &Signer{cfg: cfg, headers: headers, keys: map[string]*domainKey{}}
+	if err := s.Reload(cfg.Keys); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Check if messages are signed
+func (s *Signer) Enabled() bool {
+	return s != nil && s.cfg.Enabled
+}
+
+// Replace domain keys with the keys loaded from files, current keys are kept if any key fails to load.
+// Enabled flag and signed headers are not reloaded.
+func (s *Signer) Reload(domainKeys []config.DKIMKey) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	keys := make(map[string]*domainKey, len(domainKeys))
+	for _, k := range domainKeys {
+		key, err := loadKey(k)
+		if err != nil {
+			return errors.Wrapf(err, "dkim key of %s", k.Domain)
+		}
+		keys[strings.ToLower(k.Domain)] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Sign message of the sending domain, message is returned unchanged when domain has no key
+func (s *Signer) Sign(message []byte, domain string) ([]byte, error) {
+	if !s.Enabled() {
+		return message, nil
+	}
+
+	s.mu.RLock()
+	key, ok := s.keys[strings.ToLower(domain)]
+	s.mu.RUnlock()
+	if !ok {
+		return message, nil
+	}
+
+	fields, body := splitMessage(message)
+	bodyHash := sha256.Sum256(relaxedBody(body))
+
+	signed, names := s.signedFields(fields)
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s;\r\n\tt=%d; h=%s;\r\n\tbh=%s;\r\n\tb=",
+		key.algorithm,
+		strings.ToLower(domain),
+		key.selector,
+		time.Now().Unix(),
+		strings.Join(names, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]),
+	)
+
+	hash := sha256.New()
+	for _, field := range signed {
+		hash.Write([]byte(relaxedHeader(field)))
+	}
+	// signature header is hashed without the trailing CRLF
+	hash.Write([]byte(strings.TrimSuffix(relaxedHeader(signatureHeader+": "+value), crlf)))
+
+	signature, err := key.sign(hash.Sum(nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "domainKey.sign")
+	}
+
+	header := signatureHeader + ": " + value + fold(base64.StdEncoding.EncodeToString(signature)) + crlf
+	return append([]byte(header), message...), nil
+}
+
+// Header fields to sign and their names, multiple instances are signed from the bottom up
+func (s *Signer) signedFields(fields []string) ([]string, []string) {
+	signed := make([]string, 0, len(s.headers))
+	names := make([]string, 0, len(s.headers))
+	for _, name := range s.headers {
+		lower := strings.ToLower(name)
+		for i := len(fields) - 1; i >= 0; i-- {
+			if headerFieldName(fields[i]) == lower {
+				signed = append(signed, fields[i])
+				names = append(names, lower)
+			}
+		}
+	}
+	return signed, names
+}
+
+// RSA keys sign PKCS #1 v1.5 SHA-256 digest, Ed25519 keys sign the digest itself (RFC 8463)
+func (k *domainKey) sign(digest []byte) ([]byte, error) {
+	if _, ok := k.signer.(ed25519.PrivateKey); ok {
+		return k.signer.Sign(rand.Reader, digest, crypto.Hash(0))
+	}
+	return k.signer.Sign(rand.Reader, digest, crypto.SHA256)
+}
+
+// Load PEM encoded PKCS #1 or PKCS #8 private key
+func loadKey(cfg config.DKIMKey) (*domainKey, error) {
+	keyPEM, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "os.ReadFile")
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data in %s", cfg.KeyFile)
+	}
+
+	var key interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "x509.ParsePrivateKey")
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &domainKey{selector: cfg.Selector, algorithm: "rsa-sha256", signer: k}, nil
+	case ed25519.PrivateKey:
+		return &domainKey{selector: cfg.Selector, algorithm: "ed25519-sha256", signer: k}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %T", key)
+	}
+}
+
+// Fold base64 value to lines of the fold width
+func fold(value string) string {
+	var b strings.Builder
+	for len(value) > foldWidth {
+		b.WriteString(value[:foldWidth] + crlf + "\t ")
+		value = value[foldWidth:]
+	}
+	b.WriteString(value)
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package dkim
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rmq_service/config"
+)
+
+// RFC 6376 section 3.4.5 example
+const (
+	rfc6376Header = "A: X\r\nB : Y\t\r\n\tZ  \r\n"
+	rfc6376Body 	= " C \r\nD \t E\r\n\r\n\r\n"
+)
+
+// RFC 8463 appendix A example
+const (
+	rfc8463Seed 	= "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="
+	rfc8463Public = "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="
+	rfc8463Message = "From: Joe SixPack <joe@football.example.com>\r\n" +
+		"To: Suzie Q <suzie@shopping.example.net>\r\n" +
+		"Subject: Is dinner ready?\r\n" +
+		"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)\r\n" +
+		"Message-ID: <20030712040037.46341.5F8J@football.example.com>\r\n" +
+		"\r\n" +
+		"Hi.\r\n" +
+		"\r\n" +
+		"We lost the game.  Are you hungry yet?\r\n" +
+		"\r\n" +
+		"Joe.\r\n"
+	rfc8463Signature = "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+		" d=football.example.com; i=@football.example.com;\r\n" +
+		" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+		" subject : date : message-id : from : subject : date;\r\n" +
+		" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+		" b="
+	rfc8463B = "/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11BusFa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw=="
+	rfc8463BodyHash = "2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8="
+)
+
+func TestRelaxedHeader(t *testing.T) {
+	fields, _ := splitMessage([]byte(rfc6376Header + crlf + rfc6376Body))
+
+	want := []string{"a:X\r\n", "b:Y Z\r\n"}
+	if len(fields) != len(want) {
+		t.Fatalf("fields %q, want %d fields", fields, len(want))
+	}
+	for i, field := range fields {
+		if got := relaxedHeader(field); got != want[i] {
+			t.Errorf("relaxedHeader(%q) = %q, want %q", field, got, want[i])
+		}
+	}
+}
+
+func TestRelaxedBody(t *testing.T) {
+	_, body := splitMessage([]byte(rfc6376Header + crlf + rfc6376Body))
+
+	if got, want := string(relaxedBody(body)), " C\r\nD E\r\n"; got != want {
+		t.Fatalf("relaxedBody = %q, want %q", got, want)
+	}
+	if got := relaxedBody([]byte("\r\n\r\n")); len(got) != 0 {
+		t.Fatalf("relaxedBody of empty lines = %q, want empty", got)
+	}
+}
+
+func TestEd25519KnownAnswer(t *testing.T) {
+	fields, body := splitMessage([]byte(rfc8463Message))
+
+	bodyHash := sha256.Sum256(relaxedBody(body))
+	if got := base64.StdEncoding.EncodeToString(bodyHash[:]); got != rfc8463BodyHash {
+		t.Fatalf("body hash %s, want %s", got, rfc8463BodyHash)
+	}
+
+	seed, _ := base64.StdEncoding.DecodeString(rfc8463Seed)
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	if got := base64.StdEncoding.EncodeToString(privateKey.Public().(ed25519.PublicKey)); got != rfc8463Public {
+		t.Fatalf("public key %s, want %s", got, rfc8463Public)
+	}
+
+	// h= lists from, to, subject, date, message-id, the repeated names have no more instances
+	hash := sha256.New()
+	for _, field := range fields {
+		hash.Write([]byte(relaxedHeader(field)))
+	}
+	hash.Write([]byte(strings.TrimSuffix(relaxedHeader(rfc8463Signature), crlf)))
+
+	key := &domainKey{selector: "brisbane", algorithm: "ed25519-sha256", signer: privateKey}
+	signature, err := key.sign(hash.Sum(nil))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if got := base64.StdEncoding.EncodeToString(signature); got != rfc8463B {
+		t.Fatalf("signature %s, want %s", got, rfc8463B)
+	}
+}
+
+// Write new Ed25519 key of the test to PEM file
+func writeTestKey(t *testing.T, domain string) (config.DKIMKey, ed25519.PublicKey) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey: %v", err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), domain+".pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	return config.DKIMKey{Domain: domain, Selector: "mail", KeyFile: keyFile}, public
+}
+
+// Verify the signature added on top of the message
+func verifyTestSignature(t *testing.T, signed []byte, public ed25519.PublicKey) {
+	t.Helper()
+
+	fields, body := splitMessage(signed)
+	if headerFieldName(fields[0]) != "dkim-signature" {
+		t.Fatalf("first field %q, want DKIM-Signature", fields[0])
+	}
+
+	tags := map[string]string{}
+	for _, tag := range strings.Split(strings.Join(strings.Fields(strings.SplitN(fields[0], ":", 2)[1]), ""), ";") {
+		name, value, _ := strings.Cut(tag, "=")
+		tags[name] = value
+	}
+
+	bodyHash := sha256.Sum256(relaxedBody(body))
+	if tags["bh"] != base64.StdEncoding.EncodeToString(bodyHash[:]) {
+		t.Fatalf("body hash %s does not match the body", tags["bh"])
+	}
+
+	hash := sha256.New()
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i > 0; i-- {
+			if headerFieldName(fields[i]) == name {
+				hash.Write([]byte(relaxedHeader(fields[i])))
+				break
+			}
+		}
+	}
+	unsigned := fields[0][:strings.LastIndex(fields[0], "b=")+len("b=")]
+	hash.Write([]byte(strings.TrimSuffix(relaxedHeader(unsigned), crlf)))
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		t.Fatalf("signature %q: %v", tags["b"], err)
+	}
+	if !ed25519.Verify(public, hash.Sum(nil), signature) {
+		t.Fatal("signature does not verify")
+	}
+}
+
+func TestSignerSign(t *testing.T) {
+	key, public := writeTestKey(t, "example.com")
+	signer, err := NewSigner(config.DKIM{Enabled: true, Keys: []config.DKIMKey{key}})
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	signed, err := signer.Sign([]byte(rfc8463Message), "Example.com")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	verifyTestSignature(t, signed, public)
+
+	unsigned, err := signer.Sign([]byte(rfc8463Message), "other.com")
+	if err != nil || string(unsigned) != rfc8463Message {
+		t.Fatalf("Sign of domain without key changed the message, err %v", err)
+	}
+}
+
+func TestSignerReloadDomains(t *testing.T) {
+	key, _ := writeTestKey(t, "example.com")
+	signer, err := NewSigner(config.DKIM{Enabled: true, Keys: []config.DKIMKey{key}})
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	added, public := writeTestKey(t, "added.com")
+	if err := signer.Reload([]config.DKIMKey{added}); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	signed, err := signer.Sign([]byte(rfc8463Message), "added.com")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	verifyTestSignature(t, signed, public)
+
+	if removed, _ := signer.Sign([]byte(rfc8463Message), "example.com"); string(removed) != rfc8463Message {
+		t.Fatal("removed domain is still signed")
+	}
+
+	if err := signer.Reload([]config.DKIMKey{{Domain: "broken.com", KeyFile: "missing.pem"}}); err == nil {
+		t.Fatal("Reload of missing key file succeeded")
+	}
+	if kept, _ := signer.Sign([]byte(rfc8463Message), "added.com"); string(kept) == rfc8463Message {
+		t.Fatal("keys are not kept after failed reload")
+	}
+}
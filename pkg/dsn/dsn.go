@@ -0,0 +1,275 @@
+package dsn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Report types
+const (
+	ReportTypeDeliveryStatus 	= "delivery-status"
+	ReportTypeFeedback 				= "feedback-report"
+)
+
+// Recipient actions of the delivery status notification
+const (
+	ActionFailed 		= "failed"
+	ActionDelayed 	= "delayed"
+	ActionDelivered = "delivered"
+	ActionRelayed 	= "relayed"
+	ActionExpanded 	= "expanded"
+)
+
+var (
+	ErrNotReport = errors.New("message is not a delivery status notification or feedback report")
+
+	// header fields of the report holding its envelope recipient, where VERP address ends up
+	addresseeHeaders = []string{"To", "Delivered-To", "X-Original-To", "Envelope-To"}
+)
+
+// Delivery status notification (RFC 3464) or feedback report (RFC 5965) about the original message
+type Report struct {
+	Type 				string
+	// envelope recipients of the report and envelope sender of the original message
+	Addressees 	[]string
+	// Message-ID of the original message without angle brackets
+	MessageID 	string
+	// feedback type of the feedback report, e.g. abuse
+	FeedbackType string
+	Recipients 	[]*Recipient
+}
+
+// Recipient of the original message the report is about, feedback report recipients have no action
+type Recipient struct {
+	Address 				string
+	Action 					string
+	// enhanced status code (RFC 3463), e.g. 5.1.1
+	Status 					string
+	DiagnosticCode 	string
+}
+
+// Check if delivery failed permanently
+func (r *Recipient) IsHardBounce() bool {
+	return r.Action == ActionFailed && strings.HasPrefix(r.Status, "5")
+}
+
+// Check if delivery failed or was delayed by a transient error
+func (r *Recipient) IsSoftBounce() bool {
+	return (r.Action == ActionFailed || r.Action == ActionDelayed) && !r.IsHardBounce()
+}
+
+// Parse raw MIME message of the multipart/report, report parts may be nested inside other multiparts
+func Parse(r io.Reader) (*Report, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "mail.ReadMessage")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.Wrap(err, "mime.ParseMediaType")
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, errors.Wrap(ErrNotReport, mediaType)
+	}
+
+	report := &Report{}
+	for _, name := range addresseeHeaders {
+		report.Addressees = append(report.Addressees, parseAddresses(msg.Header[name])...)
+	}
+
+	var originalRecipients []string
+	if err := report.parseMultipart(msg.Body, params["boundary"], &originalRecipients); err != nil {
+		return nil, err
+	}
+
+	if report.Type == "" {
+		return nil, ErrNotReport
+	}
+
+	// feedback report may leave out the recipients, they are taken from the original message then,
+	// recipients redacted by the provider are left out as well
+	if report.Type == ReportTypeFeedback && len(report.Recipients) == 0 {
+		for _, address := range originalRecipients {
+			report.Recipients = append(report.Recipients, &Recipient{Address: address})
+		}
+	}
+
+	if report.Type == ReportTypeDeliveryStatus && len(report.Recipients) == 0 {
+		return nil, errors.Wrap(ErrNotReport, "no recipients")
+	}
+	return report, nil
+}
+
+// Parse parts of the multipart body
+func (r *Report) parseMultipart(body io.Reader, boundary string, originalRecipients *[]string) error {
+	if boundary == "" {
+		return errors.New("multipart boundary is missing")
+	}
+
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "mr.NextPart")
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			// parts without content type are the human readable text
+			continue
+		}
+
+		content, err := io.ReadAll(decodePart(part))
+		if err != nil {
+			return errors.Wrap(err, "io.ReadAll")
+		}
+
+		switch mediaType {
+		case "message/delivery-status", "message/global-delivery-status":
+			r.Type = ReportTypeDeliveryStatus
+			r.parseDeliveryStatus(content)
+		case "message/feedback-report":
+			r.Type = ReportTypeFeedback
+			r.parseFeedbackReport(content)
+		case "message/rfc822", "message/global", "text/rfc822-headers", "message/rfc822-headers", "message/global-headers":
+			*originalRecipients = r.parseOriginalHeaders(content)
+		default:
+			if strings.HasPrefix(mediaType, "multipart/") {
+				if err := r.parseMultipart(bytes.NewReader(content), params["boundary"], originalRecipients); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Delivery status fields are the per message group followed by a group of every recipient
+func (r *Report) parseDeliveryStatus(content []byte) {
+	groups := readFieldGroups(content)
+	if len(groups) < 2 {
+		return
+	}
+
+	for _, fields := range groups[1:] {
+		address := fieldValue(fields.Get("Final-Recipient"))
+		if address == "" {
+			address = fieldValue(fields.Get("Original-Recipient"))
+		}
+		if address == "" {
+			continue
+		}
+
+		r.Recipients = append(r.Recipients, &Recipient{
+			Address: 				normalizeAddress(address),
+			Action: 				strings.ToLower(firstToken(fields.Get("Action"))),
+			Status: 				firstToken(fields.Get("Status")),
+			DiagnosticCode: fieldValue(fields.Get("Diagnostic-Code")),
+		})
+	}
+}
+
+// Feedback report is a single group of fields, envelope sender of the original message is the VERP address
+func (r *Report) parseFeedbackReport(content []byte) {
+	groups := readFieldGroups(content)
+	if len(groups) == 0 {
+		return
+	}
+	fields := groups[0]
+
+	r.FeedbackType = strings.ToLower(firstToken(fields.Get("Feedback-Type")))
+	r.Addressees = append(r.Addressees, parseAddresses(fields["Original-Mail-From"])...)
+	for _, address := range parseAddresses(fields["Original-Rcpt-To"]) {
+		r.Recipients = append(r.Recipients, &Recipient{Address: address})
+	}
+}
+
+// Take Message-ID and return path of the original message, its recipients are returned
+func (r *Report) parseOriginalHeaders(content []byte) []string {
+	groups := readFieldGroups(content)
+	if len(groups) == 0 {
+		return nil
+	}
+	header := groups[0]
+
+	if messageID := strings.Trim(strings.TrimSpace(header.Get("Message-ID")), "<>"); messageID != "" {
+		r.MessageID = messageID
+	}
+	r.Addressees = append(r.Addressees, parseAddresses(header["Return-Path"])...)
+	return parseAddresses(header["To"])
+}
+
+// Read blank line separated groups of header fields until the end or the first malformed line
+func readFieldGroups(content []byte) []textproto.MIMEHeader {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(content)))
+	groups := make([]textproto.MIMEHeader, 0)
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			groups = append(groups, fields)
+		}
+		if err != nil {
+			return groups
+		}
+	}
+}
+
+// Decode base64 part, quoted-printable parts are decoded by the multipart reader
+func decodePart(part *multipart.Part) io.Reader {
+	if strings.EqualFold(strings.TrimSpace(part.Header.Get("Content-Transfer-Encoding")), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+	return part
+}
+
+// Value of the typed field, e.g. "rfc822; user@example.com"
+func fieldValue(value string) string {
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = value[i+1:]
+	}
+	return strings.TrimSpace(value)
+}
+
+// First token of the field value followed by a comment, e.g. "5.1.1 (bad destination mailbox)"
+func firstToken(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// Parse address lists of the header fields, values which are not address lists are taken as they are
+func parseAddresses(values []string) []string {
+	addresses := make([]string, 0, len(values))
+	for _, value := range values {
+		list, err := mail.ParseAddressList(value)
+		if err != nil {
+			if address := normalizeAddress(value); address != "" {
+				addresses = append(addresses, address)
+			}
+			continue
+		}
+
+		for _, a := range list {
+			addresses = append(addresses, normalizeAddress(a.Address))
+		}
+	}
+	return addresses
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(address), "<>"))
+}
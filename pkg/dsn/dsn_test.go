@@ -0,0 +1,129 @@
+package dsn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		file         string
+		reportType   string
+		messageID    string
+		feedbackType string
+		addressee    string
+		recipient    string
+		action       string
+		status       string
+		hard         bool
+		soft         bool
+	}{
+		{
+			file:       "hard_bounce.eml",
+			reportType: ReportTypeDeliveryStatus,
+			messageID:  "6f1c2a4e-8d3b-4b9a-9f0e-3c2d1b0a9e8f@example.com",
+			addressee:  "bounces+6f1c2a4e",
+			recipient:  "nobody@receiver.example.org",
+			action:     ActionFailed,
+			status:     "5.1.1",
+			hard:       true,
+		},
+		{
+			file:       "soft_bounce.eml",
+			reportType: ReportTypeDeliveryStatus,
+			messageID:  "0b7e5d6c-1a2f-4e3d-8c9b-7a6f5e4d3c2b@example.com",
+			recipient:  "full@busy.example.net",
+			action:     ActionDelayed,
+			status:     "4.2.2",
+			soft:       true,
+		},
+		{
+			file:       "hard_bounce_message_id.eml",
+			reportType: ReportTypeDeliveryStatus,
+			messageID:  "2d9f8e7a-6b5c-4d3e-a2f1-0e9d8c7b6a54@example.com",
+			recipient:  "gone@corp.example.com",
+			action:     ActionFailed,
+			status:     "5.1.10",
+			hard:       true,
+		},
+		{
+			file:         "complaint.eml",
+			reportType:   ReportTypeFeedback,
+			messageID:    "9e8d7c6b-5a4f-4e3d-b2c1-a0f9e8d7c6b5@example.com",
+			feedbackType: "abuse",
+			recipient:    "reader@isp.example.net",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatalf("os.Open: %v", err)
+			}
+			defer f.Close()
+
+			report, err := Parse(f)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if report.Type != tc.reportType {
+				t.Errorf("Type = %q, want %q", report.Type, tc.reportType)
+			}
+			if report.MessageID != tc.messageID {
+				t.Errorf("MessageID = %q, want %q", report.MessageID, tc.messageID)
+			}
+			if report.FeedbackType != tc.feedbackType {
+				t.Errorf("FeedbackType = %q, want %q", report.FeedbackType, tc.feedbackType)
+			}
+			if tc.addressee != "" && !hasAddressee(report.Addressees, tc.addressee) {
+				t.Errorf("Addressees = %v, want one starting with %q", report.Addressees, tc.addressee)
+			}
+
+			if len(report.Recipients) != 1 {
+				t.Fatalf("Recipients = %d, want 1", len(report.Recipients))
+			}
+			rcpt := report.Recipients[0]
+			if rcpt.Address != tc.recipient {
+				t.Errorf("Address = %q, want %q", rcpt.Address, tc.recipient)
+			}
+			if rcpt.Action != tc.action {
+				t.Errorf("Action = %q, want %q", rcpt.Action, tc.action)
+			}
+			if rcpt.Status != tc.status {
+				t.Errorf("Status = %q, want %q", rcpt.Status, tc.status)
+			}
+			if rcpt.IsHardBounce() != tc.hard {
+				t.Errorf("IsHardBounce = %v, want %v", rcpt.IsHardBounce(), tc.hard)
+			}
+			if rcpt.IsSoftBounce() != tc.soft {
+				t.Errorf("IsSoftBounce = %v, want %v", rcpt.IsSoftBounce(), tc.soft)
+			}
+		})
+	}
+}
+
+func TestParseNotReport(t *testing.T) {
+	messages := []string{
+		"From: a@example.com\r\nContent-Type: text/plain\r\n\r\nhello\r\n",
+		"From: a@example.com\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n--b\r\nContent-Type: text/plain\r\n\r\nhello\r\n--b--\r\n",
+	}
+
+	for _, msg := range messages {
+		if _, err := Parse(strings.NewReader(msg)); !errors.Is(err, ErrNotReport) {
+			t.Errorf("Parse = %v, want ErrNotReport", err)
+		}
+	}
+}
+
+func hasAddressee(addressees []string, prefix string) bool {
+	for _, a := range addressees {
+		if strings.HasPrefix(a, prefix) {
+			return true
+		}
+	}
+	return false
+}
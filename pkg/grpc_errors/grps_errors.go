@@ -15,6 +15,21 @@ var (
 	ErrNoCtxMetadata 		= errors.New("No ctx metadata")
 	ErrInvalidSessionId = errors.New("Invalid session id")
 	ErrEmailExists      = errors.New("Email already exists")
+	ErrInvalidEmailStatus = errors.New("Invalid email status transition")
+	ErrInvalidTemplate 	= errors.New("Invalid template")
+	ErrInvalidUnsubscribeToken = errors.New("Invalid unsubscribe token")
+	ErrInvalidAPIKey 		= errors.New("Invalid API key")
+	ErrTenantDisabled 	= errors.New("Tenant is disabled")
+	ErrQuotaExceeded 		= errors.New("Quota exceeded")
+	ErrInvalidTrackingToken = errors.New("Invalid tracking token")
+	ErrInvalidBounceReport 	= errors.New("Invalid bounce report")
+	ErrEmailNotMatched 			= errors.New("Delivery event matches no email")
+	ErrInvalidWebhookSignature = errors.New("Invalid webhook signature")
+	ErrInvalidWebhookPayload 	= errors.New("Invalid webhook payload")
+	ErrEmptyParkedFilter 			= errors.New("Parked emails filter is empty")
+	ErrInvalidBlobKey 				= errors.New("Invalid attachment blob key")
+	ErrNoSecretsKey 					= errors.New("Secrets key is not configured")
+	ErrInvalidWebhookURL 			= errors.New("Invalid webhook URL")
 )
 
 // Parse error and get code
@@ -22,6 +37,8 @@ func ParseGRPCErrStatusCode(err error) codes.Code {
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return codes.NotFound
+	case errors.Is(err, ErrNotFound):
+		return codes.NotFound
 	case errors.Is(err, context.Canceled):
 		return codes.Canceled
 	case errors.Is(err, context.DeadlineExceeded):
@@ -32,6 +49,36 @@ func ParseGRPCErrStatusCode(err error) codes.Code {
 		return codes.Unauthenticated
 	case errors.Is(err, ErrInvalidSessionId):
 		return codes.PermissionDenied
+	case errors.Is(err, ErrInvalidAPIKey):
+		return codes.Unauthenticated
+	case errors.Is(err, ErrTenantDisabled):
+		return codes.PermissionDenied
+	case errors.Is(err, ErrQuotaExceeded):
+		return codes.ResourceExhausted
+	case errors.Is(err, ErrInvalidEmailStatus):
+		return codes.FailedPrecondition
+	case errors.Is(err, ErrInvalidTemplate):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInvalidUnsubscribeToken):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInvalidTrackingToken):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInvalidBounceReport):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrEmailNotMatched):
+		return codes.NotFound
+	case errors.Is(err, ErrInvalidWebhookSignature):
+		return codes.Unauthenticated
+	case errors.Is(err, ErrInvalidWebhookPayload):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrEmptyParkedFilter):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInvalidBlobKey):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrNoSecretsKey):
+		return codes.FailedPrecondition
+	case errors.Is(err, ErrInvalidWebhookURL):
+		return codes.InvalidArgument
 	case strings.Contains(err.Error(), "Validate"):
 		return codes.InvalidArgument
 	case strings.Contains(err.Error(), "redis"):
@@ -59,6 +106,10 @@ func MapGRPCErrCodeToHttpStatus(code codes.Code) int {
 		return http.StatusGatewayTimeout
 	case codes.InvalidArgument:
 		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
 	}
 
 	return http.StatusInternalServerError
@@ -0,0 +1,75 @@
+package hmactoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const tokenSeparator = "."
+
+var (
+	ErrMalformedToken 	= errors.New("malformed token")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrMalformedPayload = errors.New("malformed payload")
+)
+
+// HMAC-SHA256 signer of JSON payloads, token is the base64 payload and its signature
+type Signer struct {
+	secret []byte
+}
+
+// Signer constructor
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Check if signer has the secret
+func (s *Signer) Enabled() bool {
+	return s != nil && len(s.secret) > 0
+}
+
+// Sign JSON encoded payload
+func (s *Signer) Sign(payload interface{}) (string, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", errors.Wrap(err, "json.Marshal")
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payloadBytes)
+	return encoded + tokenSeparator + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Verify token signature and decode its payload
+func (s *Signer) Verify(token string, payload interface{}) error {
+	encoded, signature, ok := strings.Cut(token, tokenSeparator)
+	if !ok {
+		return ErrMalformedToken
+	}
+
+	signatureBytes, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(signatureBytes, s.sign(encoded)) {
+		return ErrInvalidSignature
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrMalformedPayload
+	}
+
+	if err := json.Unmarshal(payloadBytes, payload); err != nil {
+		return ErrMalformedPayload
+	}
+
+	return nil
+}
+
+func (s *Signer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,72 @@
+package hmactoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testPayload struct {
+	Email string `json:"email"`
+}
+
+func TestSignerSignVerify(t *testing.T) {
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(&testPayload{Email: "to@example.com"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	payload := &testPayload{}
+	if err := signer.Verify(token, payload); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if payload.Email != "to@example.com" {
+		t.Fatalf("email %s, want to@example.com", payload.Email)
+	}
+}
+
+func TestSignerVerifyInvalid(t *testing.T) {
+	signer := NewSigner("secret")
+
+	token, err := signer.Sign(&testPayload{Email: "to@example.com"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(token, tokenSeparator)
+
+	other, err := NewSigner("other").Sign(&testPayload{Email: "to@example.com"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	tests := []struct {
+		name 	string
+		token string
+		want 	error
+	}{
+		{"no separator", encoded, ErrMalformedToken},
+		{"other secret", other, ErrInvalidSignature},
+		{"changed payload", "e30" + tokenSeparator + signature, ErrInvalidSignature},
+		{"bad signature encoding", encoded + tokenSeparator + "!", ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := signer.Verify(tt.token, &testPayload{}); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignerEnabled(t *testing.T) {
+	var nilSigner *Signer
+	if nilSigner.Enabled() || NewSigner("").Enabled() {
+		t.Fatal("signer without secret is enabled")
+	}
+	if !NewSigner("secret").Enabled() {
+		t.Fatal("signer with secret is disabled")
+	}
+}
@@ -10,3 +10,16 @@ import (
 func NewMailDialer(cfg *config.Config) *gomail.Dialer {
 	return gomail.NewDialer(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.User, cfg.Smtp.Password)
 }
+
+// MailDialers of SMTP providers constructor, dialers are keyed by provider name
+func NewMailDialers(cfg *config.Config) map[string]*gomail.Dialer {
+	providers := cfg.GetSmtpProviders()
+	dialers := make(map[string]*gomail.Dialer, len(providers))
+	for _, p := range providers {
+		if !p.IsSMTP() {
+			continue
+		}
+		dialers[p.Name] = gomail.NewDialer(p.Host, p.Port, p.User, p.Password)
+	}
+	return dialers
+}
@@ -0,0 +1,30 @@
+package minio
+
+import (
+	"context"
+	"rmq_service/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Minio AWS S3 client constructor
+func NewMinioClient(cfg *config.Config) (*minio.Client, error) {
+	return minio.New(cfg.AWS.Endpoint, &minio.Options{
+		Creds: 	credentials.NewStaticV4(cfg.AWS.MinioAccessKey, cfg.AWS.MinioSecretKey, ""),
+		Secure: cfg.AWS.UseSSL,
+	})
+}
+
+// Create bucket if it does not exist yet
+func CreateBucket(ctx context.Context, client *minio.Client, bucket string) error {
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	return client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{})
+}
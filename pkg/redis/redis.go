@@ -0,0 +1,20 @@
+package redis
+
+import (
+	"rmq_service/config"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis client constructor
+func NewRedisClient(cfg *config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: 				cfg.Redis.RedisAddr,
+		MinIdleConns: cfg.Redis.MinIdleCons,
+		PoolSize: 		cfg.Redis.PoolSize,
+		PoolTimeout: 	time.Duration(cfg.Redis.PoolTimeout) * time.Second,
+		Password: 		cfg.Redis.Password,
+		DB: 					cfg.Redis.DB,
+	})
+}
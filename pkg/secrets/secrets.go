@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"rmq_service/pkg/grpc_errors"
+
+	"github.com/pkg/errors"
+)
+
+// Prefix of the encrypted values, values without it were stored before encryption
+const encryptedPrefix = "enc:v1:"
+
+// AES-256-GCM cipher of the stored secrets, encrypted value is the prefix and base64 nonce with ciphertext
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// Cipher constructor, key is base64 encoded 32 bytes. Cipher without key can not encrypt secrets.
+func NewCipher(key string) (*Cipher, error) {
+	if key == "" {
+		return &Cipher{}, nil
+	}
+
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "base64.DecodeString")
+	}
+	if len(keyBytes) != 32 {
+		return nil, errors.Errorf("secrets key is %d bytes, want 32", len(keyBytes))
+	}
+
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "aes.NewCipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "cipher.NewGCM")
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt secret, empty secret is kept empty
+func (c *Cipher) Encrypt(secret string) (string, error) {
+	if secret == "" {
+		return "", nil
+	}
+	if c.aead == nil {
+		return "", grpc_errors.ErrNoSecretsKey
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "rand.Read")
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt secret, value stored before encryption is returned as is
+func (c *Cipher) Decrypt(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	if c.aead == nil {
+		return "", grpc_errors.ErrNoSecretsKey
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Wrap(err, "base64.DecodeString")
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	secret, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "aead.Open")
+	}
+	return string(secret), nil
+}
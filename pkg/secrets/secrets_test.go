@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"rmq_service/pkg/grpc_errors"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+	c, err := NewCipher(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestCipherEncryptDecrypt(t *testing.T) {
+	c := newTestCipher(t)
+
+	encrypted, err := c.Encrypt("smtp-password")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, encryptedPrefix) || strings.Contains(encrypted, "smtp-password") {
+		t.Fatalf("encrypted value %q", encrypted)
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil || decrypted != "smtp-password" {
+		t.Fatalf("Decrypt = %q, %v", decrypted, err)
+	}
+}
+
+func TestCipherDecryptPlain(t *testing.T) {
+	c := newTestCipher(t)
+
+	if decrypted, err := c.Decrypt("stored-before-encryption"); err != nil || decrypted != "stored-before-encryption" {
+		t.Fatalf("Decrypt = %q, %v", decrypted, err)
+	}
+}
+
+func TestCipherDecryptOtherKey(t *testing.T) {
+	encrypted, err := newTestCipher(t).Encrypt("api-key")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := newTestCipher(t).Decrypt(encrypted); err == nil {
+		t.Fatal("Decrypt with other key succeeded")
+	}
+}
+
+func TestCipherWithoutKey(t *testing.T) {
+	c, err := NewCipher("")
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	if encrypted, err := c.Encrypt(""); err != nil || encrypted != "" {
+		t.Fatalf("Encrypt of empty secret = %q, %v", encrypted, err)
+	}
+	if _, err := c.Encrypt("api-key"); !errors.Is(err, grpc_errors.ErrNoSecretsKey) {
+		t.Fatalf("Encrypt error %v, want ErrNoSecretsKey", err)
+	}
+}
+
+func TestNewCipherInvalidKey(t *testing.T) {
+	for _, key := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewCipher(key); err == nil {
+			t.Errorf("NewCipher(%q) succeeded", key)
+		}
+	}
+}
@@ -1,9 +1,36 @@
 package utils
 
-import "github.com/microcosm-cc/bluemonday"
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var (
+	htmlLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr|table|blockquote|pre)>`)
+	htmlSkippedRegex 	 = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	spacesRegex 			 = regexp.MustCompile(`[ \t]+`)
+	blankLinesRegex 	 = regexp.MustCompile(`\n{3,}`)
+)
 
 // func to sanitize string
 func SanitizeString(s string) string {
 	ugcPolicy := bluemonday.UGCPolicy()
 	return ugcPolicy.Sanitize(s)
 }
+
+// func to convert html to plain text, block elements and line breaks become new lines
+func HTMLToText(s string) string {
+	s = htmlSkippedRegex.ReplaceAllString(s, "")
+	s = htmlLineBreakRegex.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(bluemonday.StrictPolicy().Sanitize(s))
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spacesRegex.ReplaceAllString(line, " "))
+	}
+
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}